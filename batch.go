@@ -0,0 +1,419 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xgfone/go-netaddr"
+)
+
+// FindResult is the result of a single IP lookup of FindBatch.
+type FindResult struct {
+	IP     string
+	Reason string
+	// Err is either nil or the error that Find would have returned for the IP, e.g.
+	// ErrInvalidIP or ErrIPNotFound.
+	Err error
+}
+
+// FindBatch searches for all of the passed IPs within two pipelined round trips.
+// The returned slice always has the same length as ips and the result at index i belongs to ips[i].
+// Invalid IPs or IPs that are not found do not fail the whole batch, instead the Err field of the
+// corresponding result is set.
+// An error is only returned if the database could not be queried at all.
+func (c *Client) FindBatch(ctx context.Context, ips []string) ([]FindResult, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.findBatch(ctx, ips)
+}
+
+// NotFoundError is returned by FindMany for the IPs that were not found in any range.
+type NotFoundError struct {
+	IPs []string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v : %s", ErrIPNotFound, strings.Join(e.IPs, ", "))
+}
+
+// Unwrap allows to check the error with errors.Is(err, ErrIPNotFound).
+func (e *NotFoundError) Unwrap() error {
+	return ErrIPNotFound
+}
+
+// FindMany searches for all of the passed IPs like FindBatch and returns a map from IP to reason.
+// IPs that are not contained in any range are mapped to an empty reason and additionally listed
+// in the returned *NotFoundError, which wraps ErrIPNotFound. The map is returned in that case as well.
+// Any other error, e.g. ErrInvalidIP, aborts the whole lookup.
+func (c *Client) FindMany(ctx context.Context, ips []string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	results, err := c.findBatch(ctx, ips)
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := make(map[string]string, len(results))
+	var notFound []string
+	for _, result := range results {
+		if errors.Is(result.Err, ErrIPNotFound) {
+			reasons[result.IP] = ""
+			notFound = append(notFound, result.IP)
+			continue
+		} else if result.Err != nil {
+			return nil, fmt.Errorf("%s: %w", result.IP, result.Err)
+		}
+		reasons[result.IP] = result.Reason
+	}
+
+	if len(notFound) > 0 {
+		return reasons, &NotFoundError{IPs: notFound}
+	}
+	return reasons, nil
+}
+
+func (c *Client) findBatch(ctx context.Context, ips []string) ([]FindResult, error) {
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
+
+	results := make([]FindResult, len(ips))
+
+	bnds := make([]boundary, len(ips))
+	belowCmds := make([]*redis.ZSliceCmd, len(ips))
+	aboveCmds := make([]*redis.ZSliceCmd, len(ips))
+
+	tx := c.rdb.TxPipeline()
+	for idx, ip := range ips {
+		results[idx].IP = ip
+
+		ipaddr, err := netaddr.NewIPAddress(ip, 4)
+		if err != nil {
+			results[idx].Err = fmt.Errorf("%w : %v", ErrInvalidIP, err)
+			continue
+		}
+		bnds[idx] = newBoundary(ipaddr.IP(), "", true, true)
+
+		if !c.inBounds(bnds[idx]) {
+			results[idx].Err = ErrIPNotFound
+			continue
+		}
+
+		// nearest boundary below or at the IP
+		belowCmds[idx] = tx.ZRevRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
+			Min:    "-inf",
+			Max:    bnds[idx].Int64String(),
+			Offset: 0,
+			Count:  1,
+		})
+
+		// nearest boundary above the IP
+		aboveCmds[idx] = tx.ZRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
+			Min:    bnds[idx].Above().Int64String(),
+			Max:    "+inf",
+			Offset: 0,
+			Count:  1,
+		})
+	}
+
+	_, err := tx.Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	// boundaries that are shared between multiple IPs are only fetched once
+	nearest := make([][2]int, len(ips))
+	unique := make([]boundary, 0, 2*len(ips))
+	indexOf := make(map[string]int, 2*len(ips))
+
+	add := func(z redis.Z) int {
+		bnd := c.boundaryOf(z)
+		if idx, ok := indexOf[bnd.ID]; ok {
+			return idx
+		}
+		indexOf[bnd.ID] = len(unique)
+		unique = append(unique, bnd)
+		return len(unique) - 1
+	}
+
+	for idx := range ips {
+		if results[idx].Err != nil {
+			continue
+		}
+
+		below, err := belowCmds[idx].Result()
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+		above, err := aboveCmds[idx].Result()
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+
+		if len(below) == 0 || len(above) == 0 {
+			results[idx].Err = ErrDatabaseInconsistent
+			continue
+		}
+
+		nearest[idx] = [2]int{add(below[0]), add(above[0])}
+	}
+
+	err = c.fetchAttributes(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range ips {
+		if results[idx].Err != nil {
+			continue
+		}
+
+		belowNearest := unique[nearest[idx][0]]
+		aboveNearest := unique[nearest[idx][1]]
+
+		if belowNearest.Expired || aboveNearest.Expired {
+			// expired boundaries are skipped by looking further, which Find already does
+			results[idx].Reason, results[idx].Err = c.find(ctx, ips[idx])
+		} else if belowNearest.EqualIP(bnds[idx]) {
+			// the IP itself is a boundary
+			results[idx].Reason = belowNearest.Reason
+		} else if belowNearest.IsLowerBound() && aboveNearest.IsUpperBound() {
+			if belowNearest.EqualReason(aboveNearest) {
+				results[idx].Reason = belowNearest.Reason
+			} else {
+				results[idx].Err = fmt.Errorf("%w : reasons inconsistent: %s != %s", ErrDatabaseInconsistent, belowNearest.Reason, aboveNearest.Reason)
+			}
+		} else {
+			results[idx].Err = ErrIPNotFound
+		}
+	}
+
+	return results, nil
+}
+
+// parsedRange is a range whose boundaries have already been parsed.
+type parsedRange struct {
+	low, high boundary
+}
+
+// touches returns true if both ranges overlap or are adjacent to each other.
+// Such ranges affect each other's vicinity.
+func (r parsedRange) touches(other parsedRange) bool {
+	return r.low.Int64-1 <= other.high.Int64+1 && other.low.Int64-1 <= r.high.Int64+1
+}
+
+// batchWriter queues the commands of multiple ranges into a shared transaction.
+// Every range needs to see the changes of previous ranges that touch it,
+// which is why the pending transaction is executed before such a range is queued.
+type batchWriter struct {
+	tx      redis.Pipeliner
+	pending []parsedRange
+}
+
+func (c *Client) newBatchWriter(size int) *batchWriter {
+	return &batchWriter{
+		tx:      c.rdb.TxPipeline(),
+		pending: make([]parsedRange, 0, size),
+	}
+}
+
+// queue calls fn with the transaction that the commands of r must be added to.
+func (w *batchWriter) queue(ctx context.Context, r parsedRange, fn func(tx redis.Pipeliner) error) error {
+	for _, p := range w.pending {
+		if r.touches(p) {
+			err := w.flush(ctx)
+			if err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	err := fn(w.tx)
+	if err != nil {
+		return err
+	}
+	w.pending = append(w.pending, r)
+	return nil
+}
+
+// flush executes all pending commands.
+func (w *batchWriter) flush(ctx context.Context) error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	w.pending = w.pending[:0]
+
+	_, err := w.tx.Exec(ctx)
+	return err
+}
+
+// InsertBatch inserts all of the passed entries like consecutive calls of Insert would do,
+// but queues their commands into as few transactions as possible.
+// Entries that overlap or touch previous entries of the same batch need to see the changes of those
+// entries, which is why the pending transaction is executed before such an entry is queued.
+// All entries are validated before anything is inserted. In case an entry is invalid,
+// the returned error contains its index and none of the entries are inserted.
+func (c *Client) InsertBatch(ctx context.Context, entries []RangeEntry) error {
+	parsed := make([]parsedRange, 0, len(entries))
+	for idx, entry := range entries {
+		reason := c.normalizeReason(entry.Reason)
+		low, high, err := parseRange(entry.Range, reason)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", idx, err)
+		}
+		if !c.inBounds(low) || !c.inBounds(high) {
+			return fmt.Errorf("entry %d: %w : range exceeds the global boundaries", idx, ErrInvalidRange)
+		}
+		err = c.validateReason(reason)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", idx, err)
+		}
+		parsed = append(parsed, parsedRange{low, high})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := c.newBatchWriter(len(parsed))
+	for _, r := range parsed {
+		err := w.queue(ctx, r, func(tx redis.Pipeliner) error {
+			return c.queueInsert(ctx, tx, r.low, r.high)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.flush(ctx)
+}
+
+// UpsertBatch inserts or updates all of the passed entries like consecutive calls of InsertOrUpdate would do,
+// but queues their commands into as few transactions as possible like InsertBatch.
+// Entries whose exact range is already stored with a different reason are updated, entries whose exact range is
+// already stored with the same reason are left as they are and all other entries are inserted.
+// The returned counts are 0 if any of the transactions fails, even though previous transactions may have been executed.
+func (c *Client) UpsertBatch(ctx context.Context, entries []RangeEntry) (inserted, updated int, err error) {
+	parsed := make([]parsedRange, 0, len(entries))
+	for idx, entry := range entries {
+		reason := c.normalizeReason(entry.Reason)
+		low, high, err := parseRange(entry.Range, reason)
+		if err != nil {
+			return 0, 0, fmt.Errorf("entry %d: %w", idx, err)
+		}
+		if !c.inBounds(low) || !c.inBounds(high) {
+			return 0, 0, fmt.Errorf("entry %d: %w : range exceeds the global boundaries", idx, ErrInvalidRange)
+		}
+		err = c.validateReason(reason)
+		if err != nil {
+			return 0, 0, fmt.Errorf("entry %d: %w", idx, err)
+		}
+		parsed = append(parsed, parsedRange{low, high})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := c.newBatchWriter(len(parsed))
+	for _, r := range parsed {
+		err := w.queue(ctx, r, func(tx redis.Pipeliner) error {
+			below, inside, above, err := c.vicinity(ctx, r.low, r.high, 1)
+			if err != nil {
+				return err
+			}
+
+			if lowBnd, highBnd, ok := exactBoundaries(r.low, r.high, inside); ok {
+				if !lowBnd.EqualReason(r.low) {
+					c.queueReasonUpdate(ctx, tx, lowBnd, highBnd, r.low.Reason)
+					updated++
+				}
+				return nil
+			}
+
+			c.queueInsertWithin(ctx, tx, r.low, r.high, below, inside, above)
+			inserted++
+			return nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	err = w.flush(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return inserted, updated, nil
+}
+
+// RemoveBatch removes all of the passed ranges like consecutive calls of Remove would do,
+// but queues their commands into as few transactions as possible.
+// Like InsertBatch, the pending transaction is executed before a range is queued that touches a pending range.
+// All ranges are validated before anything is removed. In case a range is invalid,
+// the returned error contains its index and none of the ranges are removed.
+func (c *Client) RemoveBatch(ctx context.Context, ranges []string) error {
+	parsed := make([]parsedRange, 0, len(ranges))
+	for idx, ipRange := range ranges {
+		low, high, err := parseRange(ipRange, "")
+		if err != nil {
+			return fmt.Errorf("range %d: %w", idx, err)
+		}
+		if !c.inBounds(low) || !c.inBounds(high) {
+			return fmt.Errorf("range %d: %w : range exceeds the global boundaries", idx, ErrInvalidRange)
+		}
+		parsed = append(parsed, parsedRange{low, high})
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := c.newBatchWriter(len(parsed))
+	for _, r := range parsed {
+		err := w.queue(ctx, r, func(tx redis.Pipeliner) error {
+			return c.queueRemove(ctx, tx, r.low, r.high)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return w.flush(ctx)
+}
+
+// DeleteByReason removes all ranges with the passed reason and returns the number of removed ranges.
+// The removals are queued like in RemoveBatch. Ranges with the same reason are merged when they are inserted,
+// which is why they usually do not touch each other and are removed within a single transaction.
+// Otherwise multiple transactions are executed and a failing transaction leaves the ranges of the previous
+// transactions removed, even though the returned count is 0.
+func (c *Client) DeleteByReason(ctx context.Context, reason string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ranges, err := c.findByReason(ctx, c.normalizeReason(reason))
+	if err != nil {
+		return 0, err
+	}
+
+	w := c.newBatchWriter(len(ranges))
+	for _, r := range ranges {
+		pr := parsedRange{
+			low:  newBoundary(r.Low, "", true, false),
+			high: newBoundary(r.High, "", false, true),
+		}
+		err := w.queue(ctx, pr, func(tx redis.Pipeliner) error {
+			return c.queueRemove(ctx, tx, pr.low, pr.high)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = w.flush(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(ranges), nil
+}
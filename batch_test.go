@@ -0,0 +1,314 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClient_FindBatch(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip      string
+		reason  string
+		wantErr error
+	}{
+		{"10.0.0.0", "first", nil},
+		{"not an ip", "", ErrInvalidIP},
+		{"10.0.0.128", "first", nil},
+		{"10.0.1.4", "", ErrIPNotFound},
+		{"10.0.1.5", "second", nil},
+		{"::1", "", ErrInvalidIP},
+		{"10.0.2.10", "third", nil},
+		{"10.0.2.5", "third", nil},
+		{"10.0.2.11", "", ErrIPNotFound},
+		{"10.0.0.128", "first", nil},
+	}
+
+	ips := make([]string, 0, len(tests))
+	for _, tt := range tests {
+		ips = append(ips, tt.ip)
+	}
+
+	results, err := rdb.FindBatch(ctx, ips)
+	if err != nil {
+		t.Fatalf("rdb.FindBatch() error = %v", err)
+	}
+
+	if len(results) != len(tests) {
+		t.Fatalf("rdb.FindBatch() returned %d results, want %d", len(results), len(tests))
+	}
+
+	for idx, tt := range tests {
+		got := results[idx]
+		if got.IP != tt.ip {
+			t.Errorf("result %d: IP = %q, want %q", idx, got.IP, tt.ip)
+		}
+		if !errors.Is(got.Err, tt.wantErr) {
+			t.Errorf("result %d: Err = %v, want %v", idx, got.Err, tt.wantErr)
+		}
+		if got.Reason != tt.reason {
+			t.Errorf("result %d: Reason = %q, want %q", idx, got.Reason, tt.reason)
+		}
+
+		reason, err := rdb.Find(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) || reason != got.Reason {
+			t.Errorf("result %d: differs from rdb.Find(): %q, %v", idx, reason, err)
+		}
+	}
+}
+
+func TestClient_InsertBatch(t *testing.T) {
+	batch := initRDB(0)
+	defer batch.Close()
+
+	sequential := initRDB(1)
+	defer sequential.Close()
+
+	ctx := context.TODO()
+	entries := make([]RangeEntry, 0, len(ranges)+2)
+	for _, r := range ranges {
+		entries = append(entries, RangeEntry{Range: r.Range, Reason: r.Reason})
+	}
+	// disjoint ranges that share a transaction
+	entries = append(entries,
+		RangeEntry{Range: "1.0.0.0 - 1.0.0.10", Reason: "disjoint"},
+		RangeEntry{Range: "1.0.0.20 - 1.0.0.30", Reason: "disjoint"},
+	)
+
+	for _, entry := range entries {
+		if err := sequential.Insert(ctx, entry.Range, entry.Reason); err != nil {
+			t.Fatalf("sequential.Insert() error = %v", err)
+		}
+	}
+
+	if err := batch.InsertBatch(ctx, entries); err != nil {
+		t.Fatalf("batch.InsertBatch() error = %v", err)
+	}
+
+	want, err := sequential.all(ctx)
+	if err != nil {
+		t.Fatalf("sequential.all() error = %v", err)
+	}
+	got, err := batch.all(ctx)
+	if err != nil {
+		t.Fatalf("batch.all() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batch.InsertBatch() resulted in %v, want %v", got, want)
+	}
+
+	err = batch.InsertBatch(ctx, []RangeEntry{{"240.0.0.1", "valid"}, {"invalid", "invalid"}})
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("batch.InsertBatch() error = %v, want %v", err, ErrInvalidRange)
+	}
+	if _, err := batch.Find(ctx, "240.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("batch.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+}
+
+func TestClient_UpsertBatch(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.9", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	inserted, updated, err := rdb.UpsertBatch(ctx, []RangeEntry{
+		{"10.0.0.0/24", "updated"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.4", "inserted"},
+		{"10.0.2.5 - 10.0.2.9", "inserted"},
+		{"10.0.3.0", "inserted"},
+		{"10.0.3.0", "updated"},
+	})
+	// 10.0.2.5 - 10.0.2.9 is left over from the third range after the previous entry is inserted
+	if err != nil || inserted != 2 || updated != 3 {
+		t.Fatalf("rdb.UpsertBatch() = %d, %d, %v, want 2, 3", inserted, updated, err)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Fatalf("rdb.UpsertBatch() left the database inconsistent: %v", err)
+	}
+
+	tests := []struct {
+		ip     string
+		reason string
+	}{
+		{"10.0.0.1", "updated"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0", "inserted"},
+		{"10.0.2.9", "inserted"},
+		{"10.0.3.0", "updated"},
+	}
+	for _, tt := range tests {
+		if reason, err := rdb.Find(ctx, tt.ip); err != nil || reason != tt.reason {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", tt.ip, reason, err, tt.reason)
+		}
+	}
+
+	_, _, err = rdb.UpsertBatch(ctx, []RangeEntry{{"240.0.0.1", "valid"}, {"invalid", "invalid"}})
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("rdb.UpsertBatch() error = %v, want %v", err, ErrInvalidRange)
+	}
+}
+
+func TestClient_FindMany(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "first"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	got, err := rdb.FindMany(ctx, []string{"10.0.0.1", "10.0.1.1", "10.0.0.255", "11.0.0.0"})
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("rdb.FindMany() error = %v, want %T", err, notFound)
+	}
+	if want := []string{"10.0.1.1", "11.0.0.0"}; !reflect.DeepEqual(notFound.IPs, want) {
+		t.Errorf("NotFoundError.IPs = %v, want %v", notFound.IPs, want)
+	}
+
+	want := map[string]string{
+		"10.0.0.1":   "first",
+		"10.0.1.1":   "",
+		"10.0.0.255": "first",
+		"11.0.0.0":   "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rdb.FindMany() = %v, want %v", got, want)
+	}
+
+	got, err = rdb.FindMany(ctx, []string{"10.0.0.1"})
+	if err != nil || got["10.0.0.1"] != "first" {
+		t.Errorf("rdb.FindMany() = %v, %v, want %q", got, err, "first")
+	}
+
+	_, err = rdb.FindMany(ctx, []string{"10.0.0.1", "invalid"})
+	if !errors.Is(err, ErrInvalidIP) {
+		t.Errorf("rdb.FindMany() error = %v, want %v", err, ErrInvalidIP)
+	}
+}
+
+func TestClient_RemoveBatch(t *testing.T) {
+	batch := initRDB(0)
+	defer batch.Close()
+
+	sequential := initRDB(1)
+	defer sequential.Close()
+
+	ctx := context.TODO()
+	for _, rdb := range []*Client{batch, sequential} {
+		for _, r := range ranges {
+			if err := rdb.Insert(ctx, r.Range, r.Reason); err != nil {
+				t.Fatalf("rdb.Insert() error = %v", err)
+			}
+		}
+	}
+
+	removals := []string{
+		"10.0.0.0/8",
+		"123.0.0.2 - 123.0.0.4",
+		"123.0.0.5",
+		"123.0.0.1",
+		"200.0.0.0 - 201.0.0.0",
+		"220.0.0.0 - 221.0.0.0",
+		"250.0.0.0/8",
+	}
+
+	for _, r := range removals {
+		if err := sequential.Remove(ctx, r); err != nil {
+			t.Fatalf("sequential.Remove() error = %v", err)
+		}
+	}
+
+	if err := batch.RemoveBatch(ctx, removals); err != nil {
+		t.Fatalf("batch.RemoveBatch() error = %v", err)
+	}
+
+	want, err := sequential.all(ctx)
+	if err != nil {
+		t.Fatalf("sequential.all() error = %v", err)
+	}
+	got, err := batch.all(ctx)
+	if err != nil {
+		t.Fatalf("batch.all() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batch.RemoveBatch() resulted in %v, want %v", got, want)
+	}
+
+	if err := batch.RemoveBatch(ctx, []string{"invalid"}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("batch.RemoveBatch() error = %v, want %v", err, ErrInvalidRange)
+	}
+}
+
+func TestClient_DeleteByReason(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "delete"},
+		{"10.0.1.0/24", "keep"},
+		{"10.0.1.5", "delete"},
+		{"10.0.2.0 - 10.0.2.10", "delete"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	deleted, err := rdb.DeleteByReason(ctx, "delete")
+	if err != nil || deleted != 3 {
+		t.Fatalf("rdb.DeleteByReason() = %d, %v, want 3", deleted, err)
+	}
+
+	got, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+
+	want := []string{
+		"10.0.1.0 - 10.0.1.4 keep",
+		"10.0.1.6 - 10.0.1.255 keep",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.All() = %v, want %v", got, want)
+	}
+	for idx, r := range got {
+		if s := r.Low.String() + " - " + r.High.String() + " " + r.Reason; s != want[idx] {
+			t.Errorf("rdb.All()[%d] = %s, want %s", idx, s, want[idx])
+		}
+	}
+
+	if err := rdb.VerifyConsistency(ctx); err != nil {
+		t.Errorf("rdb.VerifyConsistency() error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// benchmarkRanges returns n ranges that do neither overlap nor touch each other.
+// Every range covers two IPs with one uncovered IP in between.
+func benchmarkRanges(n int) []RangeEntry {
+	entries := make([]RangeEntry, 0, n)
+	for i := 0; i < n; i++ {
+		ip := 0x01000000 + int64(i%(1<<28))*4
+		entries = append(entries, RangeEntry{
+			Range:  fmt.Sprintf("%s - %s", ipFromInt64(ip), ipFromInt64(ip+1)),
+			Reason: "benchmark",
+		})
+	}
+	return entries
+}
+
+// BenchmarkInsert_PipelineDepth measures the insertion throughput of InsertBatch depending on the number
+// of ranges that are passed per call. As none of the ranges overlap or touch each other, all ranges of a
+// call are queued within a single transaction.
+// All ranges are generated in advance in order to only measure the insertion.
+func BenchmarkInsert_PipelineDepth(b *testing.B) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+
+	for _, depth := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			if err := rdb.Reset(ctx); err != nil {
+				b.Fatalf("rdb.Reset() error = %v", err)
+			}
+
+			entries := benchmarkRanges(b.N)
+
+			b.ResetTimer()
+
+			for start := 0; start < len(entries); start += depth {
+				end := start + depth
+				if end > len(entries) {
+					end = len(entries)
+				}
+
+				if err := rdb.InsertBatch(ctx, entries[start:end]); err != nil {
+					b.Fatalf("rdb.InsertBatch() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkClient_Insert(b *testing.B) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	entries := benchmarkRanges(b.N)
+
+	b.ResetTimer()
+
+	for _, entry := range entries {
+		if err := rdb.Insert(ctx, entry.Range, entry.Reason); err != nil {
+			b.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkClient_Find(b *testing.B) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+
+	const numRanges = 1000
+	entries := benchmarkRanges(numRanges)
+	if err := rdb.InsertBatch(ctx, entries); err != nil {
+		b.Fatalf("rdb.InsertBatch() error = %v", err)
+	}
+
+	// alternate between IPs within the ranges and the uncovered IPs in between them
+	ips := make([]string, 0, 2*numRanges)
+	for i := 0; i < numRanges; i++ {
+		ip := 0x01000000 + int64(i)*4
+		ips = append(ips, ipFromInt64(ip+1).String(), ipFromInt64(ip+2).String())
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := rdb.Find(ctx, ips[i%len(ips)])
+		if err != nil && !errors.Is(err, ErrIPNotFound) {
+			b.Fatalf("rdb.Find() error = %v", err)
+		}
+	}
+}
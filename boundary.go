@@ -2,9 +2,11 @@ package goripr
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/xgfone/go-netaddr"
@@ -38,8 +40,20 @@ type boundary struct {
 	LowerBound bool
 	UpperBound bool
 	Reason     string
+	// ExpiresAt is the unix time in milliseconds at which the attributes of the boundary expire
+	// or 0 if the boundary is permanent. Boundaries that cut a range inherit its expiry.
+	ExpiresAt int64
+	// FieldExpiry lets the attribute fields expire instead of the whole attribute hash.
+	// It is not stored and only affects the write of the boundary.
+	FieldExpiry bool
+	// Expired is set by SetAttributes if the attributes of the boundary have expired,
+	// but its member has not been swept from the sorted set, yet.
+	Expired bool
 }
 
+// boundaryFields are the names of the fields of the attribute hash of a boundary.
+var boundaryFields = []string{"low", "high", "reason", "expires"}
+
 func newBoundary(ip interface{}, reason string, lower, upper bool) boundary {
 
 	var IP netaddr.IPAddress
@@ -100,6 +114,17 @@ func newBoundary(ip interface{}, reason string, lower, upper bool) boundary {
 	return b
 }
 
+// Key returns the key name of the hash that contains the attributes of the boundary.
+func (b boundary) Key() string {
+	return boundaryKey(b.ID)
+}
+
+// boundaryKey returns the key name of the attribute hash of the boundary with the passed ID,
+// which is the member of the boundary in the sorted set.
+func boundaryKey(id string) string {
+	return BoundaryKeyPrefix + id
+}
+
 // Int64String returns the string representation of the Int64 value
 func (b boundary) Int64String() string {
 	return strconv.FormatInt(b.Int64, 10)
@@ -180,7 +205,14 @@ func (b *boundary) Equal(other boundary) bool {
 		b.Float64 == other.Float64 &&
 		b.LowerBound == other.LowerBound &&
 		b.UpperBound == other.UpperBound &&
-		b.Reason == other.Reason
+		b.Reason == other.Reason &&
+		b.ExpiresAt == other.ExpiresAt
+}
+
+// SetExpiry copies the expiry of other to b.
+func (b *boundary) SetExpiry(other boundary) {
+	b.ExpiresAt = other.ExpiresAt
+	b.FieldExpiry = other.FieldExpiry
 }
 
 // EqualIP returns true if both IPs are equal as well as both Int64 and Float64 values.
@@ -203,35 +235,119 @@ func (b *boundary) Insert(ctx context.Context, tx redis.Pipeliner) redis.Pipelin
 			Member: b.ID,
 		},
 	)
-	tx.HMSet(ctx, b.ID,
-		map[string]interface{}{
-			"low":    b.LowerBound,
-			"high":   b.UpperBound,
-			"reason": b.Reason,
-		})
-	return tx
+	return b.Update(ctx, tx)
 }
 
 // Update adds the needed commands to the transaction in order to update the assiciated attributes of the
 // unserlying IP. The IP itself cannot be updated with this command.
+// The expiry of the attributes is set as well, which is why a permanent boundary that replaces
+// an expiring one does not keep the expiry of the replaced boundary.
 func (b *boundary) Update(ctx context.Context, tx redis.Pipeliner) redis.Pipeliner {
-	tx.HMSet(ctx, b.ID,
+	tx.HMSet(ctx, b.Key(),
 		map[string]interface{}{
-			"low":    b.LowerBound,
-			"high":   b.UpperBound,
-			"reason": b.Reason,
+			"low":     b.LowerBound,
+			"high":    b.UpperBound,
+			"reason":  b.Reason,
+			"expires": b.ExpiresAt,
 		})
+
+	switch {
+	case b.ExpiresAt == 0:
+		tx.Persist(ctx, b.Key())
+	case b.FieldExpiry:
+		// requires field expiration support of redis 7.4 or later
+		args := []interface{}{"HPEXPIREAT", b.Key(), b.ExpiresAt, "FIELDS", len(boundaryFields)}
+		for _, field := range boundaryFields {
+			args = append(args, field)
+		}
+		tx.Do(ctx, args...)
+	default:
+		tx.PExpireAt(ctx, b.Key(), time.UnixMilli(b.ExpiresAt))
+	}
 	return tx
 }
 
 // Remove adds the necessary commands to the transaction in order to be properly removed.
 func (b *boundary) Remove(ctx context.Context, tx redis.Pipeliner) redis.Pipeliner {
 	tx.ZRem(ctx, IPRangesKey, b.ID)
-	tx.Del(ctx, b.ID)
+	tx.Del(ctx, b.Key())
 	return tx
 }
 
 // Get adds the necessary commands to the transaction in order to retrieve the attributs from the database.
 func (b *boundary) Get(ctx context.Context, tx redis.Pipeliner) *redis.SliceCmd {
-	return tx.HMGet(ctx, b.ID, "low", "high", "reason")
+	return tx.HMGet(ctx, b.Key(), boundaryFields...)
+}
+
+// IsInfBound returns true if b is one of the global ±inf boundaries.
+func (b *boundary) IsInfBound() bool {
+	return b.ID == negInfBoundary.ID || b.ID == posInfBoundary.ID
+}
+
+// SetAttributes sets the lower, upper, reason and expiry attributes from the result of a Get command.
+// Boundaries that were stored without an expiry are permanent.
+// If none of the attributes exist anymore, the boundary is marked as Expired without any attributes,
+// which is why it is neither a lower nor an upper boundary and is skipped by every reader.
+func (b *boundary) SetAttributes(result []interface{}) error {
+	if len(result) != len(boundaryFields) {
+		return fmt.Errorf("expected %d result attributes, got %d", len(boundaryFields), len(result))
+	}
+
+	if isExpired(result) {
+		b.LowerBound = false
+		b.UpperBound = false
+		b.Reason = ""
+		b.ExpiresAt = 0
+		b.Expired = true
+		return nil
+	}
+
+	low := false
+	switch t := result[0].(type) {
+	case string:
+		low = t == "1"
+	case nil:
+		low = false
+	default:
+		return fmt.Errorf("unexpected type: %T", t)
+	}
+
+	high := false
+	switch t := result[1].(type) {
+	case string:
+		high = t == "1"
+	case nil:
+		high = false
+	default:
+		return fmt.Errorf("unexpected type: %T", t)
+	}
+
+	reason := ""
+	switch t := result[2].(type) {
+	case string:
+		reason = t
+	default:
+		return fmt.Errorf("unexpected type: %T", t)
+	}
+
+	expiresAt := int64(0)
+	switch t := result[3].(type) {
+	case string:
+		i, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid expiry: %v", err)
+		}
+		expiresAt = i
+	case nil:
+		expiresAt = 0
+	default:
+		return fmt.Errorf("unexpected type: %T", t)
+	}
+
+	b.LowerBound = low
+	b.UpperBound = high
+	b.Reason = reason
+	b.ExpiresAt = expiresAt
+	b.Expired = false
+	return nil
 }
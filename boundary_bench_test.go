@@ -0,0 +1,47 @@
+package goripr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// benchmarkBoundaryCommand measures the cost of adding the commands of fn to a pipeline
+// without executing it. The pipeline is discarded regularly in order to keep its memory bounded.
+func benchmarkBoundaryCommand(b *testing.B, fn func(ctx context.Context, bnd *boundary, pipe redis.Pipeliner)) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr})
+	defer client.Close()
+
+	ctx := context.TODO()
+	pipe := client.Pipeline()
+	bnd := newBoundary("10.0.0.1", "benchmark", true, false)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		fn(ctx, &bnd, pipe)
+		if i%1024 == 1023 {
+			pipe.Discard()
+		}
+	}
+}
+
+func BenchmarkBoundaryInsert(b *testing.B) {
+	benchmarkBoundaryCommand(b, func(ctx context.Context, bnd *boundary, pipe redis.Pipeliner) {
+		bnd.Insert(ctx, pipe)
+	})
+}
+
+func BenchmarkBoundaryUpdate(b *testing.B) {
+	benchmarkBoundaryCommand(b, func(ctx context.Context, bnd *boundary, pipe redis.Pipeliner) {
+		bnd.Update(ctx, pipe)
+	})
+}
+
+func BenchmarkBoundaryRemove(b *testing.B) {
+	benchmarkBoundaryCommand(b, func(ctx context.Context, bnd *boundary, pipe redis.Pipeliner) {
+		bnd.Remove(ctx, pipe)
+	})
+}
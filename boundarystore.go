@@ -0,0 +1,164 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// boundaryStore abstracts the sorted set operations that the boundary algorithm needs.
+// Writes may be deferred, e.g. by queueing them into a transaction, which is why they do not return errors.
+// The boundary algorithm only depends on this interface, which allows to implement further backends
+// without touching the algorithm.
+type boundaryStore interface {
+	// ZAddBoundary adds the boundary with its score to the sorted set without changing its attributes.
+	ZAddBoundary(b boundary)
+	// ZRemBoundary removes the boundary from the sorted set together with its attributes.
+	ZRemBoundary(b boundary)
+	// GetBoundaryAttrs sets the lower, upper and reason attributes of all passed boundaries.
+	// Boundaries whose attributes do not exist are marked as Expired.
+	GetBoundaryAttrs(bnds []boundary) error
+	// SetBoundaryAttrs stores the lower, upper and reason attributes of the boundary.
+	SetBoundaryAttrs(b boundary)
+	// ZRangeByScore returns up to count boundaries without their attributes, whose scores lie within
+	// the inclusive interval [min, max], in ascending order or in descending order if reverse is true.
+	// A count < 1 returns all of the boundaries.
+	ZRangeByScore(min, max float64, reverse bool, count int64) ([]boundary, error)
+}
+
+// insertBoundary adds the boundary to s and stores its attributes.
+func insertBoundary(s boundaryStore, b boundary) {
+	s.ZAddBoundary(b)
+	s.SetBoundaryAttrs(b)
+}
+
+// storeVicinity returns the nearest boundary below low, all boundaries from low to high and
+// the nearest boundary above high including their attributes.
+func storeVicinity(s boundaryStore, low, high boundary) (below, inside, above []boundary, err error) {
+	below, err = s.ZRangeByScore(math.Inf(-1), low.Float64-1, true, 1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	inside, err = s.ZRangeByScore(low.Float64, high.Float64, false, 0)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	above, err = s.ZRangeByScore(high.Float64+1, math.Inf(1), false, 1)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, bnds := range [][]boundary{below, inside, above} {
+		err = s.GetBoundaryAttrs(bnds)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return below, inside, above, nil
+}
+
+// redisStore reads from the database of the Client and queues all writes into tx.
+type redisStore struct {
+	c   *Client
+	ctx context.Context
+	tx  redis.Pipeliner
+}
+
+var _ boundaryStore = redisStore{}
+
+// newRedisStore creates a boundaryStore for the Client. tx may be nil if nothing is written.
+func (c *Client) newRedisStore(ctx context.Context, tx redis.Pipeliner) redisStore {
+	return redisStore{c: c, ctx: ctx, tx: tx}
+}
+
+func (s redisStore) ZAddBoundary(b boundary) {
+	s.tx.ZAdd(s.ctx, IPRangesKey,
+		redis.Z{
+			Score:  b.Float64,
+			Member: b.ID,
+		},
+	)
+}
+
+func (s redisStore) ZRemBoundary(b boundary) {
+	b.Remove(s.ctx, s.tx)
+}
+
+func (s redisStore) SetBoundaryAttrs(b boundary) {
+	b.Update(s.ctx, s.tx)
+}
+
+// GetBoundaryAttrs retrieves the attributes of all passed boundaries within a single transaction.
+func (s redisStore) GetBoundaryAttrs(bnds []boundary) error {
+	if len(bnds) == 0 {
+		return nil
+	}
+
+	tx := s.c.rdb.TxPipeline()
+
+	cmds := make([]*redis.SliceCmd, 0, len(bnds))
+	for _, bnd := range bnds {
+		cmds = append(cmds, bnd.Get(s.ctx, tx))
+	}
+
+	_, err := tx.Exec(s.ctx)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	for idx, cmd := range cmds {
+		result, err := cmd.Result()
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+
+		err = bnds[idx].SetAttributes(result)
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+	}
+	return nil
+}
+
+func (s redisStore) ZRangeByScore(min, max float64, reverse bool, count int64) ([]boundary, error) {
+	zrange := &redis.ZRangeBy{
+		Min: scoreString(min),
+		Max: scoreString(max),
+	}
+	if count > 0 {
+		zrange.Count = count
+	}
+
+	var (
+		results []redis.Z
+		err     error
+	)
+	if reverse {
+		results, err = s.c.rdb.ZRevRangeByScoreWithScores(s.ctx, IPRangesKey, zrange).Result()
+	} else {
+		results, err = s.c.rdb.ZRangeByScoreWithScores(s.ctx, IPRangesKey, zrange).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	bnds := make([]boundary, 0, len(results))
+	for _, result := range results {
+		bnds = append(bnds, s.c.boundaryOf(result))
+	}
+	return bnds, nil
+}
+
+// scoreString formats the score in the redis score syntax.
+func scoreString(score float64) string {
+	switch {
+	case math.IsInf(score, -1):
+		return "-inf"
+	case math.IsInf(score, 1):
+		return "+inf"
+	}
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
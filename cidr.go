@@ -0,0 +1,149 @@
+package goripr
+
+import (
+	"fmt"
+	"math/bits"
+	"net"
+	"sort"
+)
+
+// isCIDRAligned returns true if the range from low to high can be represented by a single CIDR prefix,
+// meaning that its size is a power of two and low is the network address of that prefix.
+func isCIDRAligned(low, high uint32) bool {
+	if low > high {
+		return false
+	}
+	size := uint64(high) - uint64(low) + 1
+	return size&(size-1) == 0 && uint64(low)%size == 0
+}
+
+// toNetworks returns the minimal list of CIDR networks in ascending order that exactly cover
+// the range from low to high.
+func toNetworks(low, high uint32) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, 1)
+	for start := uint64(low); start <= uint64(high); {
+		// largest block that starts at start, limited by the alignment of start
+		// and the number of remaining IPs
+		hostBits := 32
+		if start != 0 {
+			hostBits = bits.TrailingZeros32(uint32(start))
+		}
+		for hostBits > 0 && start+(uint64(1)<<hostBits)-1 > uint64(high) {
+			hostBits--
+		}
+
+		nets = append(nets, &net.IPNet{
+			IP:   ipFromInt64(int64(start)),
+			Mask: net.CIDRMask(32-hostBits, 32),
+		})
+		start += uint64(1) << hostBits
+	}
+	return nets
+}
+
+// RangeInfoToIPNet converts the range into the minimal list of CIDR networks that exactly cover it.
+func RangeInfoToIPNet(r RangeInfo) ([]*net.IPNet, error) {
+	low, high := r.Low.To4(), r.High.To4()
+	if low == nil || high == nil {
+		return nil, fmt.Errorf("%w : range must consist of IPv4 addresses", ErrInvalidRange)
+	}
+
+	lowInt, highInt := ipToInt64(low), ipToInt64(high)
+	if lowInt > highInt {
+		return nil, ErrInvalidRange
+	}
+	return toNetworks(uint32(lowInt), uint32(highInt)), nil
+}
+
+// HyphenRangeToCIDRList converts the passed range into the minimal list of CIDR networks in ascending order
+// that exactly cover it, e.g. 10.0.0.5 - 10.0.0.20 into 10.0.0.5/32, 10.0.0.6/31, 10.0.0.8/29, 10.0.0.16/30
+// and 10.0.0.20/32. Besides the hyphen notation, it accepts all of the other formats that Insert accepts.
+// ErrInvalidRange is returned if the range cannot be parsed. It does not require a database connection.
+func HyphenRangeToCIDRList(ipRange string) ([]*net.IPNet, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return nil, err
+	}
+	return toNetworks(uint32(low.Int64), uint32(high.Int64)), nil
+}
+
+// IPNetsToRangeInfo combines the passed networks into a single range with the passed reason.
+// The networks may be passed in any order, but they must neither overlap nor leave any gaps between each other.
+func IPNetsToRangeInfo(nets []*net.IPNet, reason string) (RangeInfo, error) {
+	if len(nets) == 0 {
+		return RangeInfo{}, fmt.Errorf("%w : no networks passed", ErrInvalidRange)
+	}
+
+	type interval struct {
+		low, high int64
+	}
+
+	intervals := make([]interval, 0, len(nets))
+	for _, n := range nets {
+		ip := n.IP.To4()
+		ones, size := n.Mask.Size()
+		if ip == nil || size != 32 {
+			return RangeInfo{}, fmt.Errorf("%w : %v is not an IPv4 network", ErrInvalidRange, n)
+		}
+
+		low := ipToInt64(ip.Mask(n.Mask))
+		intervals = append(intervals, interval{low, low + int64(1)<<(32-ones) - 1})
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].low < intervals[j].low
+	})
+
+	for idx := 1; idx < len(intervals); idx++ {
+		if intervals[idx].low != intervals[idx-1].high+1 {
+			return RangeInfo{}, fmt.Errorf("%w : networks overlap or are not contiguous", ErrInvalidRange)
+		}
+	}
+
+	return RangeInfo{
+		Low:    ipFromInt64(intervals[0].low),
+		High:   ipFromInt64(intervals[len(intervals)-1].high),
+		Reason: reason,
+	}, nil
+}
+
+// AggregateToCIDRs returns the minimal list of CIDR networks in ascending order that exactly cover all of the
+// passed ranges. The ranges may be passed in any order and in any of the formats that Insert accepts.
+// Overlapping and adjacent ranges are merged before they are split into networks, which is why the networks
+// never overlap. The reasons of the entries are ignored. Nothing is returned if any of the ranges is invalid.
+func AggregateToCIDRs(ranges []RangeEntry) ([]*net.IPNet, error) {
+	type interval struct {
+		low, high int64
+	}
+
+	intervals := make([]interval, 0, len(ranges))
+	for idx, entry := range ranges {
+		low, high, err := parseRange(entry.Range, "")
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", idx, err)
+		}
+		intervals = append(intervals, interval{low.Int64, high.Int64})
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].low < intervals[j].low
+	})
+
+	merged := make([]interval, 0, len(intervals))
+	for _, i := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && i.low <= merged[last].high+1 {
+			if i.high > merged[last].high {
+				merged[last].high = i.high
+			}
+			continue
+		}
+		merged = append(merged, i)
+	}
+
+	nets := make([]*net.IPNet, 0, len(merged))
+	for _, i := range merged {
+		nets = append(nets, toNetworks(uint32(i.low), uint32(i.high))...)
+	}
+	return nets, nil
+}
@@ -0,0 +1,159 @@
+package goripr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"testing"
+)
+
+func TestIsCIDRAligned(t *testing.T) {
+	tests := []struct {
+		low, high uint32
+		want      bool
+	}{
+		{0, math.MaxUint32, true},
+		{0, 0, true},
+		{5, 5, true},
+		{4, 7, true},
+		{2, 5, false},
+		{0, 2, false},
+		{256, 511, true},
+		{128, 383, false},
+		{7, 6, false},
+	}
+	for _, tt := range tests {
+		if got := isCIDRAligned(tt.low, tt.high); got != tt.want {
+			t.Errorf("isCIDRAligned(%d, %d) = %v, want %v", tt.low, tt.high, got, tt.want)
+		}
+	}
+}
+
+func TestRangeInfoToIPNet(t *testing.T) {
+	tests := []struct {
+		low, high string
+		want      []string
+		wantErr   error
+	}{
+		{"10.0.0.0", "10.0.0.255", []string{"10.0.0.0/24"}, nil},
+		{"10.0.0.1", "10.0.0.1", []string{"10.0.0.1/32"}, nil},
+		{"10.0.0.1", "10.0.0.6", []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"}, nil},
+		{"0.0.0.0", "255.255.255.255", []string{"0.0.0.0/0"}, nil},
+		{"10.0.0.255", "10.0.2.0", []string{"10.0.0.255/32", "10.0.1.0/24", "10.0.2.0/32"}, nil},
+		{"10.0.0.9", "10.0.0.0", nil, ErrInvalidRange},
+		{"::1", "::2", nil, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		nets, err := RangeInfoToIPNet(RangeInfo{Low: net.ParseIP(tt.low), High: net.ParseIP(tt.high)})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("RangeInfoToIPNet(%s - %s) error = %v, want %v", tt.low, tt.high, err, tt.wantErr)
+			continue
+		}
+		got := make([]string, 0, len(nets))
+		for _, n := range nets {
+			got = append(got, n.String())
+		}
+		if tt.wantErr == nil && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("RangeInfoToIPNet(%s - %s) = %v, want %v", tt.low, tt.high, got, tt.want)
+		}
+	}
+}
+
+func TestHyphenRangeToCIDRList(t *testing.T) {
+	tests := []struct {
+		ipRange string
+		want    []string
+		wantErr error
+	}{
+		{"10.0.0.5 - 10.0.0.20", []string{"10.0.0.5/32", "10.0.0.6/31", "10.0.0.8/29", "10.0.0.16/30", "10.0.0.20/32"}, nil},
+		{"10.0.0.0 - 10.0.0.255", []string{"10.0.0.0/24"}, nil},
+		{"10.0.0.1", []string{"10.0.0.1/32"}, nil},
+		{"10.0.0.0/23", []string{"10.0.0.0/23"}, nil},
+		{"10.0.0.20 - 10.0.0.5", nil, ErrInvalidRange},
+		{"invalid", nil, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		nets, err := HyphenRangeToCIDRList(tt.ipRange)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("HyphenRangeToCIDRList(%s) error = %v, want %v", tt.ipRange, err, tt.wantErr)
+			continue
+		}
+		got := make([]string, 0, len(nets))
+		for _, n := range nets {
+			got = append(got, n.String())
+		}
+		if tt.wantErr == nil && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("HyphenRangeToCIDRList(%s) = %v, want %v", tt.ipRange, got, tt.want)
+		}
+	}
+}
+
+func TestIPNetsToRangeInfo(t *testing.T) {
+	tests := []struct {
+		cidrs     []string
+		low, high string
+		wantErr   error
+	}{
+		{[]string{"10.0.0.0/24"}, "10.0.0.0", "10.0.0.255", nil},
+		{[]string{"10.0.1.0/24", "10.0.0.0/24", "10.0.2.0/32"}, "10.0.0.0", "10.0.2.0", nil},
+		{[]string{"10.0.0.0/24", "10.0.2.0/24"}, "", "", ErrInvalidRange},
+		{[]string{"10.0.0.0/23", "10.0.1.0/24"}, "", "", ErrInvalidRange},
+		{[]string{"::/64"}, "", "", ErrInvalidRange},
+		{nil, "", "", ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		nets := make([]*net.IPNet, 0, len(tt.cidrs))
+		for _, cidr := range tt.cidrs {
+			_, n, err := net.ParseCIDR(cidr)
+			if err != nil {
+				t.Fatalf("net.ParseCIDR(%s) error = %v", cidr, err)
+			}
+			nets = append(nets, n)
+		}
+
+		r, err := IPNetsToRangeInfo(nets, "reason")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("IPNetsToRangeInfo(%v) error = %v, want %v", tt.cidrs, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr == nil && (r.Low.String() != tt.low || r.High.String() != tt.high || r.Reason != "reason") {
+			t.Errorf("IPNetsToRangeInfo(%v) = %v, want %s - %s", tt.cidrs, r, tt.low, tt.high)
+		}
+	}
+}
+
+func TestAggregateToCIDRs(t *testing.T) {
+	tests := []struct {
+		ranges  []string
+		want    []string
+		wantErr error
+	}{
+		{nil, []string{}, nil},
+		{[]string{"10.0.1.0/24", "10.0.0.0/24"}, []string{"10.0.0.0/23"}, nil},
+		{[]string{"10.0.0.0 - 10.0.0.200", "10.0.0.100 - 10.0.0.255"}, []string{"10.0.0.0/24"}, nil},
+		{[]string{"10.0.0.0/24", "10.0.0.5"}, []string{"10.0.0.0/24"}, nil},
+		{[]string{"10.0.0.1 - 10.0.0.3", "10.0.0.4 - 10.0.0.6"}, []string{"10.0.0.1/32", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/32"}, nil},
+		{[]string{"10.0.2.0/24", "10.0.0.0/24"}, []string{"10.0.0.0/24", "10.0.2.0/24"}, nil},
+		{[]string{"10.0.0.0/24", "invalid"}, nil, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		entries := make([]RangeEntry, 0, len(tt.ranges))
+		for _, r := range tt.ranges {
+			entries = append(entries, RangeEntry{Range: r})
+		}
+
+		nets, err := AggregateToCIDRs(entries)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("AggregateToCIDRs(%v) error = %v, want %v", tt.ranges, err, tt.wantErr)
+			continue
+		}
+		got := make([]string, 0, len(nets))
+		for _, n := range nets {
+			got = append(got, n.String())
+		}
+		if tt.wantErr == nil && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("AggregateToCIDRs(%v) = %v, want %v", tt.ranges, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Command goripr-cli provides maintenance tasks for goripr databases.
+//
+//	goripr-cli migrate -from redis://old-host:6379 -to redis://new-host:6379 -batch-size 1000
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `usage: goripr-cli <command> [flags]
+
+commands:
+  migrate    copy all ranges from one database into another
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1], os.Args[2:], os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run executes the passed command with its arguments and writes its progress to w.
+func run(ctx context.Context, command string, args []string, w io.Writer) error {
+	switch command {
+	case "migrate":
+		return runMigrate(ctx, args, w)
+	}
+	return fmt.Errorf("unknown command %q\n%s", command, usage)
+}
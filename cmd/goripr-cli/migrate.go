@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jxsl13/goripr/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// migrateConfig contains the flags of the migrate command.
+type migrateConfig struct {
+	from      string
+	to        string
+	batchSize int64
+	cursor    uint64
+	retries   int
+}
+
+// runMigrate parses the flags of the migrate command, connects to both databases and migrates the ranges.
+func runMigrate(ctx context.Context, args []string, w io.Writer) error {
+	var cfg migrateConfig
+
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.StringVar(&cfg.from, "from", "", "redis URL of the source database, e.g. redis://old-host:6379/0")
+	fs.StringVar(&cfg.to, "to", "", "redis URL of the destination database, e.g. redis://new-host:6379/0")
+	fs.Int64Var(&cfg.batchSize, "batch-size", 1000, "number of boundaries that are read and inserted per page")
+	fs.Uint64Var(&cfg.cursor, "cursor", 0, "cursor of an interrupted migration to resume from")
+	fs.IntVar(&cfg.retries, "retries", 3, "number of retries of a failing page before giving up")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if cfg.from == "" || cfg.to == "" {
+		return errors.New("migrate: -from and -to are required")
+	}
+	if cfg.batchSize < 1 {
+		return errors.New("migrate: -batch-size must be positive")
+	}
+
+	src, err := connect(ctx, cfg.from)
+	if err != nil {
+		return fmt.Errorf("migrate: source: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := connect(ctx, cfg.to)
+	if err != nil {
+		return fmt.Errorf("migrate: destination: %w", err)
+	}
+	defer dst.Close()
+
+	return migrate(ctx, src, dst, cfg, w)
+}
+
+// connect creates a Client for the database of the passed redis URL.
+func connect(ctx context.Context, rawURL string) (*goripr.Client, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", goripr.ErrInvalidOptions, err)
+	}
+
+	return goripr.NewClient(ctx, goripr.Options{
+		Network:   opts.Network,
+		Addr:      opts.Addr,
+		Username:  opts.Username,
+		Password:  opts.Password,
+		DB:        opts.DB,
+		TLSConfig: opts.TLSConfig,
+	})
+}
+
+// migrate transfers the ranges of src page by page into dst with goripr.TransferFrom, starting at cfg.cursor.
+// Every page is inserted with InsertBatch on top of the ranges that dst already contains.
+// A failing transfer is resumed at the first page that has not been copied up to cfg.retries times in a row.
+// If it still fails or ctx is canceled, the returned error contains the cursor that resumes the migration.
+func migrate(ctx context.Context, src, dst *goripr.Client, cfg migrateConfig, w io.Writer) error {
+	total, err := src.CountRanges(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	// the ranges below the cursor were migrated by an earlier run
+	skipped, err := rangesBefore(ctx, src, cfg.cursor)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	var (
+		start    = time.Now()
+		done     = skipped
+		cursor   = cfg.cursor
+		failures int
+	)
+	for {
+		migrated := done
+		n, next, err := goripr.TransferFrom(ctx, src, dst, cursor, cfg.batchSize, func(transferred int) {
+			printProgress(w, migrated+int64(transferred), total, migrated-skipped+int64(transferred), time.Since(start))
+		})
+		done += int64(n)
+		cursor = next
+		if err == nil {
+			break
+		}
+
+		if n > 0 {
+			failures = 0
+		}
+		if failures == cfg.retries || ctx.Err() != nil {
+			fmt.Fprintln(w)
+			return fmt.Errorf("migrate: %w, resume with -cursor %d", err, cursor)
+		}
+		failures++
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w)
+			return fmt.Errorf("migrate: %w, resume with -cursor %d", ctx.Err(), cursor)
+		case <-time.After(time.Duration(failures) * time.Second):
+		}
+	}
+
+	fmt.Fprintf(w, "\nmigrated %d ranges in %s\n", done-skipped, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// rangesBefore returns the number of ranges of src that lie completely below the cursor of a Scanner.
+func rangesBefore(ctx context.Context, src *goripr.Client, cursor uint64) (int64, error) {
+	if cursor == 0 {
+		return 0, nil
+	}
+
+	last := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(last, uint32(cursor-1))
+
+	ranges, err := src.FindContainedBy(ctx, fmt.Sprintf("0.0.0.0 - %s", last))
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ranges)), nil
+}
+
+// printProgress overwrites the current line of w with a progress bar and the throughput of the migrated ranges.
+// done includes the ranges of earlier runs and may exceed total when ranges are inserted into the source
+// during the migration. migrated only counts the ranges of the current run.
+func printProgress(w io.Writer, done, total, migrated int64, elapsed time.Duration) {
+	const width = 30
+
+	filled := width
+	if total > 0 && done < total {
+		filled = int(done * width / total)
+	}
+
+	rate := float64(migrated)
+	if elapsed > 0 {
+		rate /= elapsed.Seconds()
+	}
+	fmt.Fprintf(w, "\r[%s%s] %d/%d ranges, %.0f ranges/s",
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled), done, total, rate)
+}
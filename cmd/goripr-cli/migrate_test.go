@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jxsl13/goripr/v2"
+)
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	srcAddr, dstAddr := miniredis.RunT(t).Addr(), miniredis.RunT(t).Addr()
+
+	src, err := connect(ctx, "redis://"+srcAddr)
+	if err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+	defer src.Close()
+
+	inserts := []goripr.RangeEntry{
+		{Range: "10.0.0.0/24", Reason: "first"},
+		{Range: "10.0.1.5", Reason: "second"},
+		{Range: "10.0.2.0 - 10.0.2.10", Reason: "third"},
+	}
+	if err := src.InsertBatch(ctx, inserts); err != nil {
+		t.Fatalf("src.InsertBatch() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	args := []string{"-from", "redis://" + srcAddr, "-to", "redis://" + dstAddr + "/0", "-batch-size", "2"}
+	if err := run(ctx, "migrate", args, &out); err != nil {
+		t.Fatalf("run(migrate) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "3/3 ranges") {
+		t.Errorf("run(migrate) output = %q, want the final progress", out.String())
+	}
+
+	dst, err := connect(ctx, "redis://"+dstAddr)
+	if err != nil {
+		t.Fatalf("connect() error = %v", err)
+	}
+	defer dst.Close()
+
+	want, _ := src.All(ctx)
+	got, _ := dst.All(ctx)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dst.All() = %v, want %v", got, want)
+	}
+
+	// resuming after the first range only migrates the remaining ranges
+	if err := dst.Reset(ctx); err != nil {
+		t.Fatalf("dst.Reset() error = %v", err)
+	}
+	s := src.Scanner(ctx, 1)
+	if !s.Next(ctx) {
+		t.Fatalf("Scanner.Next() = false, error = %v", s.Err())
+	}
+	out.Reset()
+	cfg := migrateConfig{batchSize: 1, cursor: s.Cursor()}
+	if err := migrate(ctx, src, dst, cfg, &out); err != nil {
+		t.Fatalf("migrate() error = %v", err)
+	}
+	got, _ = dst.All(ctx)
+	if !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("dst.All() = %v, want %v", got, want[1:])
+	}
+	// the progress includes the range of the earlier run
+	if !strings.Contains(out.String(), "3/3 ranges") || !strings.Contains(out.String(), "migrated 2 ranges") {
+		t.Errorf("migrate() output = %q, want the progress of the resumed migration", out.String())
+	}
+}
+
+func TestRun_InvalidArguments(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		command string
+		args    []string
+	}{
+		{"unknown", nil},
+		{"migrate", nil},
+		{"migrate", []string{"-from", "redis://localhost:6379", "-to", "redis://localhost:6379", "-batch-size", "0"}},
+		{"migrate", []string{"-from", "invalid://localhost", "-to", "redis://localhost:6379"}},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := run(ctx, tt.command, tt.args, &out); err == nil {
+			t.Errorf("run(%s, %v) error = nil, want an error", tt.command, tt.args)
+		}
+	}
+}
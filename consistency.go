@@ -0,0 +1,258 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// VerifyConsistency cross-checks the enumeration of all stored ranges against Find.
+// For every range the IP in the middle of the range is looked up and its reason must
+// match the reason of the enumerated range.
+// ErrDatabaseInconsistent is returned for the first range that does not match.
+func (c *Client) VerifyConsistency(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range ranges {
+		low, high := ipToInt64(r.Low), ipToInt64(r.High)
+		mid := ipFromInt64(low + (high-low)/2).String()
+
+		reason, err := c.find(ctx, mid)
+		if errors.Is(err, ErrIPNotFound) {
+			return fmt.Errorf("%w : %s of range %s - %s was not found", ErrDatabaseInconsistent, mid, r.Low, r.High)
+		} else if err != nil {
+			return err
+		}
+
+		if reason != r.Reason {
+			return fmt.Errorf("%w : %s of range %s - %s has the reason %q instead of %q",
+				ErrDatabaseInconsistent, mid, r.Low, r.High, reason, r.Reason)
+		}
+	}
+	return nil
+}
+
+// VerifyHashExistence returns the sorted set members whose boundary attributes do not exist.
+// Such members are either left behind by ranges that were inserted with InsertWithFieldTTL and have expired,
+// in which case SweepExpired removes them, or they indicate that the database has been corrupted.
+func (c *Client) VerifyHashExistence(ctx context.Context) (missing []string, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.missingAttributes(ctx)
+}
+
+// ConsistencyReport is the result of IsConsistent.
+type ConsistencyReport struct {
+	// Consistent is true if no errors were found.
+	Consistent bool
+	// Errors describes every violation that was found.
+	Errors []string
+	// BoundaryCount is the number of boundaries including the ±inf boundaries.
+	BoundaryCount int
+}
+
+// IsConsistent checks the structure of all stored boundaries: the ±inf boundaries must be present,
+// lower and upper boundaries must alternate and the reason of every upper boundary must match the
+// reason of its lower boundary. In contrast to VerifyConsistency the check does not stop at the first violation.
+// A report is returned even if the database is inconsistent, an error only if the boundaries could not be retrieved.
+func (c *Client) IsConsistent(ctx context.Context) (*ConsistencyReport, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	errs := consistencyErrors(bnds)
+	return &ConsistencyReport{
+		Consistent:    len(errs) == 0,
+		Errors:        errs,
+		BoundaryCount: len(bnds),
+	}, nil
+}
+
+// consistencyErrors checks the sorted boundaries and returns a description of every violation.
+func consistencyErrors(bnds []boundary) []string {
+	errs := make([]string, 0)
+
+	if len(bnds) == 0 || bnds[0].ID != negInfBoundary.ID {
+		errs = append(errs, "database inconsistent: missing -inf boundary")
+	}
+	if len(bnds) == 0 || bnds[len(bnds)-1].ID != posInfBoundary.ID {
+		errs = append(errs, "database inconsistent: missing +inf boundary")
+	}
+
+	const LowerBound = 0
+	const UpperBound = 1
+
+	// state is the kind of the previous single boundary, the -inf boundary must be the first upper boundary
+	state := LowerBound
+	for idx, bnd := range bnds {
+		if bnd.LowerBound && bnd.UpperBound {
+			if state != UpperBound {
+				errs = append(errs, fmt.Sprintf("database inconsistent: double boundary: idx=%d ip=%s, expected an upper boundary", idx, bnd.ID))
+			}
+			state = UpperBound
+		} else if bnd.LowerBound {
+			if state != UpperBound {
+				errs = append(errs, fmt.Sprintf("database inconsistent: lower boundary: idx=%d ip=%s, expected an upper boundary", idx, bnd.ID))
+			}
+			state = LowerBound
+		} else if bnd.UpperBound {
+			if state != LowerBound {
+				errs = append(errs, fmt.Sprintf("database inconsistent: upper boundary: idx=%d ip=%s, expected a lower boundary", idx, bnd.ID))
+			}
+
+			// reasons consistent
+			if idx > 0 && bnd.Reason != bnds[idx-1].Reason {
+				errs = append(errs, fmt.Sprintf("reason mismatch: idx=%4d reason=%q idx=%4d reason=%q", idx-1, bnds[idx-1].Reason, idx, bnd.Reason))
+			}
+			state = UpperBound
+		} else {
+			errs = append(errs, fmt.Sprintf("database inconsistent: boundary without flags: idx=%d ip=%s", idx, bnd.ID))
+		}
+	}
+
+	if state != LowerBound {
+		errs = append(errs, "database inconsistent: final boundary is supposed to be a lower boundary")
+	}
+	return errs
+}
+
+// RepairReport is the result of Repair.
+type RepairReport struct {
+	// Actions describes every change that was made in order to repair the database.
+	Actions []string
+}
+
+// Repair tries to fix an inconsistent database on a best-effort basis by removing
+//   - sorted set members that do not match their score, e.g. duplicate members of the same IP,
+//   - boundaries without any attributes,
+//   - lower boundaries that are not followed by an upper boundary and
+//   - upper boundaries that are not preceded by a lower boundary.
+//
+// Missing ±inf boundaries are recreated. Reasons that differ between the lower and upper boundary of
+// a range are not changed. Removing boundaries may remove or shrink ranges, which is why Repair may lose data.
+// The database is locked for the whole repair.
+func (c *Client) Repair(ctx context.Context) (*RepairReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &RepairReport{Actions: make([]string, 0)}
+
+	results, err := c.rdb.ZRangeWithScores(ctx, IPRangesKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	tx := c.rdb.TxPipeline()
+	bnds := make([]boundary, 0, len(results))
+	for _, result := range results {
+		bnd := c.boundaryOf(result)
+		member := fmt.Sprint(result.Member)
+		if bnd.ID != member {
+			tx.ZRem(ctx, IPRangesKey, member)
+			tx.Del(ctx, boundaryKey(member))
+			report.Actions = append(report.Actions, fmt.Sprintf("removed member %s with the score of %s", member, bnd.ID))
+			continue
+		}
+		bnds = append(bnds, bnd)
+	}
+
+	err = c.fetchAvailableAttributes(ctx, bnds)
+	if err != nil {
+		return nil, err
+	}
+
+	remove := func(bnd boundary, problem string) {
+		bnd.Remove(ctx, tx)
+		report.Actions = append(report.Actions, fmt.Sprintf("removed %s %s", problem, bnd.ID))
+	}
+
+	var (
+		pending        *boundary
+		negInf, posInf bool
+	)
+	for idx := range bnds {
+		bnd := bnds[idx]
+		switch {
+		case bnd.ID == negInfBoundary.ID:
+			negInf = true
+		case bnd.ID == posInfBoundary.ID:
+			posInf = true
+		case !bnd.LowerBound && !bnd.UpperBound:
+			remove(bnd, "boundary without attributes")
+		case bnd.LowerBound:
+			if pending != nil {
+				remove(*pending, "orphaned lower boundary")
+				pending = nil
+			}
+			if !bnd.UpperBound {
+				pending = &bnds[idx]
+			}
+		case pending == nil:
+			remove(bnd, "orphaned upper boundary")
+		default:
+			pending = nil
+		}
+	}
+	if pending != nil {
+		remove(*pending, "orphaned lower boundary")
+	}
+
+	if len(report.Actions) > 0 {
+		_, err = tx.Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !negInf || !posInf {
+		err = c.init(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrDatabaseInit, err)
+		}
+		report.Actions = append(report.Actions, "recreated the ±inf boundaries")
+	}
+	return report, nil
+}
+
+// fetchAvailableAttributes retrieves the attributes of all passed boundaries like fetchAttributes,
+// but leaves the attributes of boundaries whose attributes are missing or invalid unset.
+func (c *Client) fetchAvailableAttributes(ctx context.Context, bnds []boundary) error {
+	tx := c.rdb.TxPipeline()
+
+	cmds := make([]*redis.SliceCmd, 0, len(bnds))
+	for _, bnd := range bnds {
+		cmds = append(cmds, bnd.Get(ctx, tx))
+	}
+
+	_, err := tx.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	for idx, cmd := range cmds {
+		result, err := cmd.Result()
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+
+		if bnds[idx].SetAttributes(result) != nil {
+			bnds[idx].LowerBound = false
+			bnds[idx].UpperBound = false
+			bnds[idx].Reason = ""
+		}
+	}
+	return nil
+}
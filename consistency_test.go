@@ -0,0 +1,167 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestClient_VerifyConsistency(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	for _, r := range ranges {
+		if err := rdb.Insert(ctx, r.Range, r.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	if err := rdb.VerifyConsistency(ctx); err != nil {
+		t.Fatalf("rdb.VerifyConsistency() error = %v", err)
+	}
+
+	// corrupt the upper boundary of a range
+	if err := rdb.rdb.HSet(ctx, boundaryKey("123.10.177.145"), "reason", "corrupted").Err(); err != nil {
+		t.Fatalf("HSet() error = %v", err)
+	}
+
+	err := rdb.VerifyConsistency(ctx)
+	if !errors.Is(err, ErrDatabaseInconsistent) {
+		t.Fatalf("rdb.VerifyConsistency() error = %v, want %v", err, ErrDatabaseInconsistent)
+	}
+}
+
+func TestClient_VerifyHashExistence(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	missing, err := rdb.VerifyHashExistence(ctx)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("rdb.VerifyHashExistence() = %v, %v, want no missing hashes", missing, err)
+	}
+
+	if err := rdb.rdb.Del(ctx, boundaryKey("10.0.0.255"), boundaryKey("10.0.1.5")).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+
+	missing, err = rdb.VerifyHashExistence(ctx)
+	if err != nil || fmt.Sprint(missing) != "[10.0.0.255 10.0.1.5]" {
+		t.Errorf("rdb.VerifyHashExistence() = %v, %v, want [10.0.0.255 10.0.1.5]", missing, err)
+	}
+}
+
+func TestClient_IsConsistent(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	report, err := rdb.IsConsistent(ctx)
+	if err != nil || !report.Consistent || len(report.Errors) != 0 || report.BoundaryCount != 5 {
+		t.Fatalf("rdb.IsConsistent() = %+v, %v, want a consistent report with 5 boundaries", report, err)
+	}
+
+	// corrupt the reason of an upper boundary and remove the +inf boundary
+	if err := rdb.rdb.HSet(ctx, boundaryKey("10.0.0.255"), "reason", "corrupted").Err(); err != nil {
+		t.Fatalf("HSet() error = %v", err)
+	}
+	if err := rdb.rdb.ZRem(ctx, IPRangesKey, "+inf").Err(); err != nil {
+		t.Fatalf("ZRem() error = %v", err)
+	}
+
+	report, err = rdb.IsConsistent(ctx)
+	if err != nil {
+		t.Fatalf("rdb.IsConsistent() error = %v", err)
+	}
+	if report.Consistent || len(report.Errors) != 3 || report.BoundaryCount != 4 {
+		t.Errorf("rdb.IsConsistent() = %+v, want an inconsistent report with 3 errors and 4 boundaries", report)
+	}
+}
+
+func TestClient_Repair(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	report, err := rdb.Repair(ctx)
+	if err != nil || len(report.Actions) != 0 {
+		t.Fatalf("rdb.Repair() = %v, %v, want no actions", report, err)
+	}
+
+	corrupt := func(ip string, fields ...interface{}) {
+		bnd := newBoundary(ip, "", false, false)
+		if err := rdb.rdb.ZAdd(ctx, IPRangesKey, redis.Z{Score: bnd.Float64, Member: ip}).Err(); err != nil {
+			t.Fatalf("ZAdd() error = %v", err)
+		}
+		if len(fields) > 0 {
+			if err := rdb.rdb.HSet(ctx, boundaryKey(ip), fields...).Err(); err != nil {
+				t.Fatalf("HSet() error = %v", err)
+			}
+		}
+	}
+	corrupt("10.0.2.0", "low", false, "high", true, "reason", "orphaned upper")
+	corrupt("10.0.3.0", "low", true, "high", false, "reason", "orphaned lower")
+	corrupt("10.0.4.0")
+
+	// duplicate member of the score of 10.0.0.0
+	first := newBoundary("10.0.0.0", "", false, false)
+	if err := rdb.rdb.ZAdd(ctx, IPRangesKey, redis.Z{Score: first.Float64, Member: "duplicate"}).Err(); err != nil {
+		t.Fatalf("ZAdd() error = %v", err)
+	}
+	if err := rdb.rdb.ZRem(ctx, IPRangesKey, "+inf").Err(); err != nil {
+		t.Fatalf("ZRem() error = %v", err)
+	}
+
+	report, err = rdb.Repair(ctx)
+	if err != nil {
+		t.Fatalf("rdb.Repair() error = %v", err)
+	}
+	if len(report.Actions) != 5 {
+		t.Errorf("rdb.Repair() actions = %v, want 5 actions", report.Actions)
+	}
+
+	consistency, err := rdb.IsConsistent(ctx)
+	if err != nil || !consistency.Consistent {
+		t.Errorf("rdb.IsConsistent() = %+v, %v after repair", consistency, err)
+	}
+	for _, ir := range inserts {
+		if reason, err := rdb.Find(ctx, strings.Split(ir.Range, "/")[0]); err != nil || reason != ir.Reason {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", ir.Range, reason, err, ir.Reason)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package goripr
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redacted = "<redacted>"
+
+// debugHook logs all commands that are processed by the redis client.
+type debugHook struct {
+	logger *slog.Logger
+}
+
+func (h debugHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h debugHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.log(ctx, cmd, time.Since(start), false)
+		return err
+	}
+}
+
+func (h debugHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		duration := time.Since(start)
+		for _, cmd := range cmds {
+			h.log(ctx, cmd, duration, true)
+		}
+		return err
+	}
+}
+
+// log logs a single processed command. The duration of pipelined commands is the duration of the whole pipeline.
+func (h debugHook) log(ctx context.Context, cmd redis.Cmder, duration time.Duration, pipelined bool) {
+	attrs := []any{
+		slog.String("cmd", cmd.Name()),
+		slog.Any("args", redactedArgs(cmd)),
+		slog.Duration("duration", duration),
+		slog.Bool("pipelined", pipelined),
+	}
+	if err := cmd.Err(); err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	h.logger.DebugContext(ctx, "redis command", attrs...)
+}
+
+// redactedArgs returns the arguments of cmd without its name and with passwords replaced.
+func redactedArgs(cmd redis.Cmder) []interface{} {
+	args := cmd.Args()
+	if len(args) == 0 {
+		return nil
+	}
+	result := make([]interface{}, len(args)-1)
+	copy(result, args[1:])
+
+	switch strings.ToLower(cmd.Name()) {
+	case "auth":
+		// AUTH [username] password
+		if len(result) > 0 {
+			result[len(result)-1] = redacted
+		}
+	case "hello":
+		// HELLO [protover [AUTH username password] [SETNAME clientname]]
+		for idx := 0; idx+2 < len(result); idx++ {
+			if s, ok := result[idx].(string); ok && strings.EqualFold(s, "auth") {
+				result[idx+2] = redacted
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package goripr
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestWithDebugLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ctx := context.TODO()
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr}, WithDebugLogging(logger))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "logged"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	for _, want := range []string{"cmd=ping", "cmd=zadd", "cmd=hmset", "pipelined=true", "logged"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRedactedArgs(t *testing.T) {
+	ctx := context.TODO()
+	tests := []struct {
+		cmd  redis.Cmder
+		want []interface{}
+	}{
+		{redis.NewStatusCmd(ctx, "auth", "secret"), []interface{}{redacted}},
+		{redis.NewStatusCmd(ctx, "auth", "user", "secret"), []interface{}{"user", redacted}},
+		{redis.NewMapStringInterfaceCmd(ctx, "hello", 3, "auth", "user", "secret", "setname", "name"), []interface{}{3, "auth", "user", redacted, "setname", "name"}},
+		{redis.NewStringCmd(ctx, "get", "secret"), []interface{}{"secret"}},
+	}
+	for _, tt := range tests {
+		if got := redactedArgs(tt.cmd); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("redactedArgs(%v) = %v, want %v", tt.cmd.Args(), got, tt.want)
+		}
+	}
+}
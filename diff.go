@@ -0,0 +1,43 @@
+package goripr
+
+// DiffSnapshots compares two snapshots of the database, e.g. two results of All.
+// added contains the ranges of b that are not part of a and removed contains the ranges
+// of a that are not part of b. Two ranges are equal if their first IP, last IP and reason match.
+// The order of both snapshots is preserved.
+func DiffSnapshots(a, b []RangeInfo) (added, removed []RangeInfo) {
+	type key struct {
+		low, high, reason string
+	}
+	keyOf := func(r RangeInfo) key {
+		return key{r.Low.String(), r.High.String(), r.Reason}
+	}
+
+	inA := make(map[key]int, len(a))
+	for _, r := range a {
+		inA[keyOf(r)]++
+	}
+
+	inB := make(map[key]int, len(b))
+	for _, r := range b {
+		inB[keyOf(r)]++
+	}
+
+	for _, r := range b {
+		k := keyOf(r)
+		if inA[k] > 0 {
+			inA[k]--
+			continue
+		}
+		added = append(added, r)
+	}
+
+	for _, r := range a {
+		k := keyOf(r)
+		if inB[k] > 0 {
+			inB[k]--
+			continue
+		}
+		removed = append(removed, r)
+	}
+	return added, removed
+}
@@ -0,0 +1,41 @@
+package goripr
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	r := func(low, high, reason string) RangeInfo {
+		return RangeInfo{Low: net.ParseIP(low).To4(), High: net.ParseIP(high).To4(), Reason: reason}
+	}
+
+	a := []RangeInfo{
+		r("10.0.0.0", "10.0.0.255", "first"),
+		r("10.0.1.0", "10.0.1.255", "second"),
+		r("10.0.2.0", "10.0.2.0", "third"),
+	}
+	b := []RangeInfo{
+		r("10.0.0.0", "10.0.0.255", "first"),
+		r("10.0.1.0", "10.0.1.127", "second"),
+		r("10.0.2.0", "10.0.2.0", "changed"),
+		r("10.0.3.0", "10.0.3.255", "fourth"),
+	}
+
+	added, removed := DiffSnapshots(a, b)
+
+	wantAdded := []RangeInfo{b[1], b[2], b[3]}
+	wantRemoved := []RangeInfo{a[1], a[2]}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("DiffSnapshots() added = %v, want %v", added, wantAdded)
+	}
+	if !reflect.DeepEqual(removed, wantRemoved) {
+		t.Errorf("DiffSnapshots() removed = %v, want %v", removed, wantRemoved)
+	}
+
+	added, removed = DiffSnapshots(a, a)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("DiffSnapshots() of equal snapshots = %v, %v, want no differences", added, removed)
+	}
+}
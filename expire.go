@@ -0,0 +1,226 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// InsertWithFieldTTL inserts the IP range like InsertWithTTL, but lets the fields of the attribute hashes
+// of the boundaries expire instead of the whole hashes.
+// Field expiration requires redis 7.4 or later. The support is checked before anything is inserted,
+// which is why older servers fail with an error without inserting the range.
+//
+// Expired boundaries leave orphaned members in the sorted set behind, which must be cleaned up
+// with SweepExpired. Until then, Find returns ErrRangeExpired for IPs within the expired range, while all other
+// methods treat the expired boundaries as if they were not stored.
+func (c *Client) InsertWithFieldTTL(ctx context.Context, ipRange, reason string, ttl time.Duration) error {
+	return c.insertExpiring(ctx, ipRange, reason, ttl, true)
+}
+
+// InsertWithTTL inserts the IP range like Insert and lets the attribute hashes of the boundaries of the
+// resulting range expire after ttl. If the new range is merged with adjacent ranges that have the same reason,
+// the boundaries of the merged range expire. In contrast to InsertWithFieldTTL the whole hash keys expire,
+// which is supported by every redis version.
+// The expiry is stored as an attribute of the boundaries, which is why boundaries that later inserts or removals
+// create within the range expire together with it, while boundaries of ranges that are inserted permanently
+// on top of it do not expire.
+//
+// Expired boundaries leave orphaned members in the sorted set behind, which must be cleaned up
+// with PurgeExpired, CleanupExpired or SweepExpired. Until then, Find returns ErrRangeExpired for IPs within
+// the expired range, while all other methods treat the expired boundaries as if they were not stored.
+func (c *Client) InsertWithTTL(ctx context.Context, ipRange, reason string, ttl time.Duration) error {
+	return c.insertExpiring(ctx, ipRange, reason, ttl, false)
+}
+
+// insertExpiring inserts the range like Insert within a single transaction together with the expiry of its boundaries.
+func (c *Client) insertExpiring(ctx context.Context, ipRange, reason string, ttl time.Duration, fieldExpiry bool) error {
+	if ttl <= 0 {
+		return ErrInvalidTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reason = c.normalizeReason(reason)
+
+	low, high, err := parseRange(ipRange, reason)
+	if err != nil {
+		return err
+	}
+
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	if fieldExpiry {
+		err = c.checkFieldExpiry(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	expiresAt := time.Now().Add(ttl).UnixMilli()
+	for _, bnd := range []*boundary{&low, &high} {
+		bnd.ExpiresAt = expiresAt
+		bnd.FieldExpiry = fieldExpiry
+	}
+
+	return c.insert(ctx, low, high)
+}
+
+// checkFieldExpiry returns an error if the server does not support field expiration.
+// The check is not part of the insert transaction, as not every server rejects unknown commands gracefully
+// within MULTI. It expires a field of a key that never exists, which does not change anything.
+func (c *Client) checkFieldExpiry(ctx context.Context) error {
+	err := c.rdb.Do(ctx, "HPEXPIREAT", BoundaryKeyPrefix+"field-expiry-check", 0, "FIELDS", 1, "low").Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	return nil
+}
+
+// SweepExpired removes the sorted set members of expired boundaries every interval
+// until ctx is done. It blocks and is supposed to be run in its own goroutine.
+// An interval < 1 defaults to one second.
+// ctx.Err() is returned when ctx is done, any other error aborts the sweeping.
+func (c *Client) SweepExpired(ctx context.Context, interval time.Duration) error {
+	if interval < 1 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		_, err := c.CleanupExpired(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// isExpired returns true if none of the attributes of a boundary exist anymore.
+func isExpired(attributes []interface{}) bool {
+	for _, attr := range attributes {
+		if attr != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestStored returns up to num boundaries including their attributes whose scores lie within the interval
+// [min, max] in ascending order or in descending order if reverse is true. num must be positive.
+// Expired boundaries that have not been swept, yet, are skipped by fetching further boundaries.
+func (c *Client) nearestStored(ctx context.Context, min, max float64, reverse bool, num int64) ([]boundary, error) {
+	store := c.newRedisStore(ctx, nil)
+	for count := num; ; count *= 2 {
+		bnds, err := store.ZRangeByScore(min, max, reverse, count)
+		if err != nil {
+			return nil, err
+		}
+		err = store.GetBoundaryAttrs(bnds)
+		if err != nil {
+			return nil, err
+		}
+
+		stored := make([]boundary, 0, num)
+		for _, bnd := range bnds {
+			if !bnd.Expired && int64(len(stored)) < num {
+				stored = append(stored, bnd)
+			}
+		}
+		if int64(len(stored)) == num || int64(len(bnds)) < count {
+			return stored, nil
+		}
+	}
+}
+
+// PurgeExpired removes the sorted set members of all expired boundaries once like CleanupExpired,
+// without reporting the number of removed members.
+func (c *Client) PurgeExpired(ctx context.Context) error {
+	_, err := c.CleanupExpired(ctx)
+	return err
+}
+
+// CleanupExpired removes the sorted set members of all expired boundaries once and returns the number
+// of removed members. It is the manual alternative to SweepExpired and may be called concurrently with
+// any other method of the Client.
+func (c *Client) CleanupExpired(ctx context.Context) (removed int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.purgeExpired(ctx)
+}
+
+// purgeExpired removes all members of the sorted set whose boundary attributes do not exist anymore
+// and returns the number of removed members.
+func (c *Client) purgeExpired(ctx context.Context) (int, error) {
+	missing, err := c.missingAttributes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	expired := make([]interface{}, 0, len(missing))
+	for _, member := range missing {
+		expired = append(expired, member)
+	}
+
+	err = c.rdb.ZRem(ctx, IPRangesKey, expired...).Err()
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	return len(expired), nil
+}
+
+// missingAttributes returns all members of the sorted set except for the ±inf boundaries
+// whose attribute hashes do not exist.
+func (c *Client) missingAttributes(ctx context.Context) ([]string, error) {
+	members, err := c.rdb.ZRange(ctx, IPRangesKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	tx := c.rdb.TxPipeline()
+	cmds := make([]*redis.IntCmd, len(members))
+	for idx, member := range members {
+		if member == negInfBoundary.ID || member == posInfBoundary.ID {
+			continue
+		}
+		cmds[idx] = tx.Exists(ctx, boundaryKey(member))
+	}
+
+	_, err = tx.Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	missing := make([]string, 0)
+	for idx, cmd := range cmds {
+		if cmd != nil && cmd.Val() == 0 {
+			missing = append(missing, members[idx])
+		}
+	}
+	return missing, nil
+}
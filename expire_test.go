@@ -0,0 +1,369 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestClient_InsertWithFieldTTL(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	err := rdb.InsertWithFieldTTL(ctx, "10.0.0.0/24", "temporary", 0)
+	if !errors.Is(err, ErrInvalidTTL) {
+		t.Fatalf("rdb.InsertWithFieldTTL() error = %v, want %v", err, ErrInvalidTTL)
+	}
+
+	err = rdb.InsertWithFieldTTL(ctx, "10.0.0.0/24", "temporary", time.Hour)
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "unknown command") {
+		t.Skipf("field expiration is not supported by the redis server: %v", err)
+	} else if err != nil {
+		t.Fatalf("rdb.InsertWithFieldTTL() error = %v", err)
+	}
+
+	reason, err := rdb.Find(ctx, "10.0.0.1")
+	if err != nil || reason != "temporary" {
+		t.Fatalf("rdb.Find() = %q, %v, want %q", reason, err, "temporary")
+	}
+}
+
+func TestClient_SweepExpired(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "temporary"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.1.0/24", "permanent"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	// simulate the expiration of the boundary fields
+	if err := rdb.rdb.Del(ctx, boundaryKey("10.0.0.0"), boundaryKey("10.0.0.255")).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+
+	sweepCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err := rdb.SweepExpired(sweepCtx, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("rdb.SweepExpired() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+	if reason, err := rdb.Find(ctx, "10.0.1.1"); err != nil || reason != "permanent" {
+		t.Errorf("rdb.Find() = %q, %v, want %q", reason, err, "permanent")
+	}
+	if err := rdb.VerifyConsistency(ctx); err != nil {
+		t.Errorf("rdb.VerifyConsistency() error = %v", err)
+	}
+}
+
+func TestClient_FindExpired(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "temporary"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	// simulate the expiration of the boundary fields
+	if err := rdb.rdb.Del(ctx, boundaryKey("10.0.0.0"), boundaryKey("10.0.0.255")).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+
+	for _, ip := range []string{"10.0.0.0", "10.0.0.1", "10.0.0.255"} {
+		if _, err := rdb.Find(ctx, ip); !errors.Is(err, ErrRangeExpired) {
+			t.Errorf("rdb.Find(%s) error = %v, want %v", ip, err, ErrRangeExpired)
+		}
+	}
+
+	if _, err := rdb.purgeExpired(ctx); err != nil {
+		t.Fatalf("rdb.purgeExpired() error = %v", err)
+	}
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+}
+
+func TestClient_CleanupExpired(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "temporary"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.1.0/24", "permanent"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	removed, err := rdb.CleanupExpired(ctx)
+	if err != nil || removed != 0 {
+		t.Fatalf("rdb.CleanupExpired() = %d, %v, want 0", removed, err)
+	}
+
+	// simulate the expiration of the boundary fields
+	if err := rdb.rdb.Del(ctx, boundaryKey("10.0.0.0"), boundaryKey("10.0.0.255")).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+
+	removed, err = rdb.CleanupExpired(ctx)
+	if err != nil || removed != 2 {
+		t.Fatalf("rdb.CleanupExpired() = %d, %v, want 2", removed, err)
+	}
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+	if reason, err := rdb.Find(ctx, "10.0.1.1"); err != nil || reason != "permanent" {
+		t.Errorf("rdb.Find() = %q, %v, want %q", reason, err, "permanent")
+	}
+}
+
+func TestClient_InsertWithTTL(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	err := rdb.InsertWithTTL(ctx, "10.0.0.0/24", "temporary", 0)
+	if !errors.Is(err, ErrInvalidTTL) {
+		t.Fatalf("rdb.InsertWithTTL() error = %v, want %v", err, ErrInvalidTTL)
+	}
+
+	if err := rdb.Insert(ctx, "10.0.1.0/24", "permanent"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.InsertWithTTL(ctx, "10.0.0.0/24", "temporary", time.Hour); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+
+	for _, key := range []string{"10.0.0.0", "10.0.0.255"} {
+		ttl, err := rdb.rdb.TTL(ctx, boundaryKey(key)).Result()
+		if err != nil || ttl <= 0 || ttl > time.Hour {
+			t.Errorf("TTL(%s) = %v, %v, want a TTL of up to one hour", key, ttl, err)
+		}
+	}
+	for _, key := range []string{"10.0.1.0", "10.0.1.255"} {
+		ttl, err := rdb.rdb.TTL(ctx, boundaryKey(key)).Result()
+		if err != nil || ttl >= 0 {
+			t.Errorf("TTL(%s) = %v, %v, want no TTL", key, ttl, err)
+		}
+	}
+
+	// simulate the expiration of the boundary hashes
+	if err := rdb.rdb.Del(ctx, boundaryKey("10.0.0.0"), boundaryKey("10.0.0.255")).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+	if err := rdb.PurgeExpired(ctx); err != nil {
+		t.Fatalf("rdb.PurgeExpired() error = %v", err)
+	}
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Errorf("rdb.PurgeExpired() left the database inconsistent: %v", err)
+	}
+}
+
+func TestClient_InsertNextToTTLRange(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	ctx := context.TODO()
+	rdb, err := NewClient(ctx, Options{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.InsertWithTTL(ctx, "10.0.0.10 - 10.0.0.20", "x", time.Minute); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+	if err := rdb.InsertWithTTL(ctx, "10.0.1.0/24", "merged", time.Minute); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+	if err := rdb.InsertWithTTL(ctx, "10.0.3.0/24", "split", time.Minute); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+
+	// cuts the expiring ranges with permanent boundaries
+	if err := rdb.Insert(ctx, "10.0.0.20 - 10.0.0.30", "y"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.Remove(ctx, "10.0.0.15"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+	// both parts expire with the split range
+	if err := rdb.SplitRange(ctx, "10.0.3.100", "lower", "upper"); err != nil {
+		t.Fatalf("rdb.SplitRange() error = %v", err)
+	}
+	// merges with the expiring range, which becomes permanent
+	if err := rdb.Insert(ctx, "10.0.1.128 - 10.0.2.10", "merged"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	mr.FastForward(2 * time.Minute)
+
+	if _, err := rdb.CleanupExpired(ctx); err != nil {
+		t.Fatalf("rdb.CleanupExpired() error = %v", err)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Fatalf("rdb.CleanupExpired() left the database inconsistent: %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr error
+	}{
+		{"10.0.0.12", "", ErrIPNotFound},
+		{"10.0.0.19", "", ErrIPNotFound},
+		{"10.0.0.20", "y", nil},
+		{"10.0.0.30", "y", nil},
+		{"10.0.1.0", "merged", nil},
+		{"10.0.2.10", "merged", nil},
+		{"10.0.3.0", "", ErrIPNotFound},
+		{"10.0.3.100", "", ErrIPNotFound},
+		{"10.0.3.101", "", ErrIPNotFound},
+		{"10.0.3.255", "", ErrIPNotFound},
+	}
+	for _, tt := range tests {
+		got, err := rdb.Find(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q, %v", tt.ip, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_ReadExpired(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	ctx := context.TODO()
+	rdb, err := NewClient(ctx, Options{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.InsertWithTTL(ctx, "10.0.0.0/24", "temporary", time.Minute); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.2.0/24", "permanent"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	// the expired boundaries are not swept
+	mr.FastForward(2 * time.Minute)
+
+	results, err := rdb.FindBatch(ctx, []string{"10.0.0.0", "10.0.0.1", "10.0.2.1"})
+	if err != nil {
+		t.Fatalf("rdb.FindBatch() error = %v", err)
+	}
+	wantResults := []FindResult{
+		{IP: "10.0.0.0", Err: ErrRangeExpired},
+		{IP: "10.0.0.1", Err: ErrRangeExpired},
+		{IP: "10.0.2.1", Reason: "permanent"},
+	}
+	for idx, want := range wantResults {
+		got := results[idx]
+		if got.IP != want.IP || got.Reason != want.Reason || !errors.Is(got.Err, want.Err) {
+			t.Errorf("rdb.FindBatch()[%d] = %+v, want %+v", idx, got, want)
+		}
+	}
+
+	var buf strings.Builder
+	if err := rdb.ExportJSON(ctx, &buf); err != nil {
+		t.Fatalf("rdb.ExportJSON() error = %v", err)
+	}
+	wantJSON := `[{"low":"10.0.2.0","high":"10.0.2.255","reason":"permanent"}` + "\n]\n"
+	if buf.String() != wantJSON {
+		t.Errorf("rdb.ExportJSON() = %q, want %q", buf.String(), wantJSON)
+	}
+
+	ranges, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	if len(ranges) != 1 || ranges[0].Reason != "permanent" {
+		t.Errorf("rdb.All() = %v, want only the permanent range", ranges)
+	}
+
+	count, err := rdb.CountRanges(ctx)
+	if err != nil || count != 1 {
+		t.Errorf("rdb.CountRanges() = %d, %v, want 1", count, err)
+	}
+}
+
+func TestClient_ExpiredNeighbours(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	ctx := context.TODO()
+	rdb, err := NewClient(ctx, Options{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.InsertWithTTL(ctx, "10.0.0.0/24", "temporary", time.Minute); err != nil {
+		t.Fatalf("rdb.InsertWithTTL() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.2.0/24", "permanent"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	// the expired boundaries are not swept
+	mr.FastForward(2 * time.Minute)
+
+	// only IPs within the expired range are reported as expired
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrRangeExpired) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrRangeExpired)
+	}
+	if _, err := rdb.Find(ctx, "10.0.1.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+
+	// the expired neighbours are treated as if they were not stored
+	if err := rdb.Insert(ctx, "10.0.1.0/24", "new"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.200 - 10.0.0.255", "overlapping"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if err := rdb.Remove(ctx, "10.0.2.0 - 10.0.2.9"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr error
+	}{
+		{"10.0.0.200", "overlapping", nil},
+		{"10.0.1.0", "new", nil},
+		{"10.0.1.255", "new", nil},
+		{"10.0.2.0", "", ErrIPNotFound},
+		{"10.0.2.10", "permanent", nil},
+	}
+	for _, tt := range tests {
+		got, err := rdb.Find(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q, %v", tt.ip, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := rdb.CleanupExpired(ctx); err != nil {
+		t.Fatalf("rdb.CleanupExpired() error = %v", err)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Fatalf("rdb.CleanupExpired() left the database inconsistent: %v", err)
+	}
+}
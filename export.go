@@ -0,0 +1,266 @@
+package goripr
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ExportThrottled writes all stored ranges to w like ExportJSON does, which is why the output can be
+// imported again with ImportJSON. At most rate ranges are read from the database per second, which keeps the
+// response buffers of the server small while the export runs alongside live traffic.
+func (c *Client) ExportThrottled(ctx context.Context, w io.Writer, rate int64) error {
+	if rate < 1 {
+		return fmt.Errorf("rate must be positive, got %d", rate)
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// a page of 2*rate boundaries contains at most rate ranges
+	return c.exportJSON(ctx, w, 2*rate, func() error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			return nil
+		}
+	})
+}
+
+// jsonRange is the representation of a single range within the JSON array of ExportJSON.
+type jsonRange struct {
+	Low    string `json:"low"`
+	High   string `json:"high"`
+	Reason string `json:"reason"`
+}
+
+// exportPageSize is the number of boundaries that ExportJSON fetches per page.
+const exportPageSize = 1024
+
+// ExportJSON writes all stored ranges to w as a single JSON array of {"low", "high", "reason"} objects
+// in ascending order. The ranges are fetched page by page and every range is encoded as soon as it is read,
+// which is why neither all ranges nor the whole array are kept in memory at once.
+// Ranges that are changed while the export runs may or may not be part of the array.
+func (c *Client) ExportJSON(ctx context.Context, w io.Writer) error {
+	return c.exportJSON(ctx, w, exportPageSize, nil)
+}
+
+// exportJSON writes the JSON array of ExportJSON to w and fetches pageSize boundaries per page.
+// If wait is not nil, it is called before every page but the first one.
+func (c *Client) exportJSON(ctx context.Context, w io.Writer, pageSize int64, wait func() error) error {
+	_, err := io.WriteString(w, "[")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	first := true
+
+	s := c.Scanner(ctx, pageSize)
+	for s.Next(ctx) {
+		for _, r := range s.RangeInfos() {
+			if !first {
+				_, err = io.WriteString(w, ",")
+				if err != nil {
+					return err
+				}
+			}
+			first = false
+
+			err = enc.Encode(jsonRange{
+				Low:    r.Low.String(),
+				High:   r.High.String(),
+				Reason: r.Reason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if wait == nil || s.done {
+			continue
+		}
+		err = wait()
+		if err != nil {
+			return err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// ImportJSON reads a JSON array as written by ExportJSON from r and inserts its ranges with InsertBatch.
+// If merge is false, the database is reset before the ranges are inserted, otherwise the ranges are inserted
+// like Insert would insert them on top of the stored ranges. The whole array is decoded and validated
+// before anything is reset or inserted. An error that wraps ErrInvalidRange and contains the index of the entry
+// is returned for the first entry that cannot be decoded or parsed.
+// The reset and the insertion are not atomic, which is why a failing insertion may leave an empty database behind.
+func (c *Client) ImportJSON(ctx context.Context, r io.Reader, merge bool) error {
+	dec := json.NewDecoder(r)
+
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidRange, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%w : expected a JSON array", ErrInvalidRange)
+	}
+
+	entries := make([]RangeEntry, 0)
+	for idx := 0; dec.More(); idx++ {
+		var jr jsonRange
+		err = dec.Decode(&jr)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w : %v", idx, ErrInvalidRange, err)
+		}
+
+		entry, err := rangeEntryOf(jr.Low, jr.High, jr.Reason)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", idx, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	_, err = dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidRange, err)
+	}
+
+	return c.importEntries(ctx, entries, merge)
+}
+
+// rangeEntryOf combines the first and last IP of a range into a validated RangeEntry.
+func rangeEntryOf(low, high, reason string) (RangeEntry, error) {
+	entry := RangeEntry{
+		Range:  low + " - " + high,
+		Reason: reason,
+	}
+	if low == high {
+		entry.Range = low
+	}
+
+	_, _, err := parseRange(entry.Range, entry.Reason)
+	if err != nil {
+		return RangeEntry{}, err
+	}
+	return entry, nil
+}
+
+// importEntries inserts the already validated entries after resetting the database, unless merge is true.
+func (c *Client) importEntries(ctx context.Context, entries []RangeEntry, merge bool) error {
+	if !merge {
+		err := c.Reset(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return c.InsertBatch(ctx, entries)
+}
+
+// ExportCSV writes all stored ranges to w as CSV with the header row low,high,reason followed by
+// one row per range in ascending order. The ranges are fetched page by page like in ExportJSON.
+func (c *Client) ExportCSV(ctx context.Context, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"low", "high", "reason"})
+	if err != nil {
+		return err
+	}
+
+	s := c.Scanner(ctx, exportPageSize)
+	for s.Next(ctx) {
+		for _, r := range s.RangeInfos() {
+			err = cw.Write([]string{r.Low.String(), r.High.String(), r.Reason})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV reads CSV rows of low,high,reason as written by ExportCSV from r and inserts them like ImportJSON.
+// The header row is optional and detected by its first field not being an IP. Empty reasons are stored as is.
+// An error that wraps ErrInvalidRange and contains the index of the row is returned for the first row
+// that cannot be read or parsed, where the first row after the optional header has the index 0.
+func (c *Client) ImportCSV(ctx context.Context, r io.Reader, merge bool) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+
+	entries := make([]RangeEntry, 0)
+	for idx := 0; ; idx++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("entry %d: %w : %v", len(entries), ErrInvalidRange, err)
+		}
+
+		if idx == 0 && net.ParseIP(record[0]) == nil {
+			// header row
+			continue
+		}
+
+		entry, err := rangeEntryOf(record[0], record[1], record[2])
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return c.importEntries(ctx, entries, merge)
+}
+
+// ImportTextFile parses a plain text blocklist from r, which contains one range per line in any of the formats
+// that Insert accepts. Everything from a # up to the end of the line is a comment and blank lines are skipped.
+// The returned entries have empty reasons and can be inserted with InsertBatch.
+// All lines are parsed before an error is returned, which joins the errors of every invalid line
+// together with its line number, starting at 1. No entries are returned in that case.
+// It does not require a database connection, which allows to validate blocklists before importing them.
+func ImportTextFile(r io.Reader) ([]RangeEntry, error) {
+	var (
+		entries = make([]RangeEntry, 0)
+		errs    []error
+		s       = bufio.NewScanner(r)
+	)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		_, _, err := parseRange(text, "")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		entries = append(entries, RangeEntry{Range: text})
+	}
+	if err := s.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("%w : %v", ErrInvalidRange, err))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return entries, nil
+}
@@ -0,0 +1,243 @@
+package goripr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_ExportThrottled(t *testing.T) {
+	src := initRDB(0)
+	defer src.Close()
+
+	dst := initRDB(1)
+	defer dst.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second \"quoted\"\nline"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := src.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("src.Insert() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportThrottled(ctx, &buf, 100); err != nil {
+		t.Fatalf("src.ExportThrottled() error = %v", err)
+	}
+
+	// the output of ExportThrottled is the same as the one of ExportJSON
+	var exported bytes.Buffer
+	if err := src.ExportJSON(ctx, &exported); err != nil {
+		t.Fatalf("src.ExportJSON() error = %v", err)
+	}
+	if buf.String() != exported.String() {
+		t.Errorf("src.ExportThrottled() = %s, want %s", buf.String(), exported.String())
+	}
+
+	if err := dst.ImportJSON(ctx, &buf, false); err != nil {
+		t.Fatalf("dst.ImportJSON() error = %v", err)
+	}
+
+	want, _ := src.All(ctx)
+	got, _ := dst.All(ctx)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("imported ranges = %v, want %v", got, want)
+	}
+}
+
+func TestClient_ExportJSON(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+
+	var empty bytes.Buffer
+	if err := rdb.ExportJSON(ctx, &empty); err != nil {
+		t.Fatalf("rdb.ExportJSON() error = %v", err)
+	}
+	var none []jsonRange
+	if err := json.Unmarshal(empty.Bytes(), &none); err != nil || len(none) != 0 {
+		t.Errorf("rdb.ExportJSON() of an empty database = %q, %v, want []", empty.String(), err)
+	}
+
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rdb.ExportJSON(ctx, &buf); err != nil {
+		t.Fatalf("rdb.ExportJSON() error = %v", err)
+	}
+
+	var got []jsonRange
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v: %s", err, buf.String())
+	}
+	want := []jsonRange{
+		{"10.0.0.0", "10.0.0.255", "first"},
+		{"10.0.1.5", "10.0.1.5", "second"},
+		{"10.0.2.0", "10.0.2.10", "third"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rdb.ExportJSON() = %v, want %v", got, want)
+	}
+}
+
+func TestClient_ImportJSON(t *testing.T) {
+	src := initRDB(0)
+	defer src.Close()
+
+	dst := initRDB(1)
+	defer dst.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := src.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("src.Insert() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportJSON(ctx, &buf); err != nil {
+		t.Fatalf("src.ExportJSON() error = %v", err)
+	}
+	exported := buf.String()
+
+	if err := dst.Insert(ctx, "192.168.0.0/24", "existing"); err != nil {
+		t.Fatalf("dst.Insert() error = %v", err)
+	}
+	if err := dst.ImportJSON(ctx, bytes.NewBufferString(exported), true); err != nil {
+		t.Fatalf("dst.ImportJSON() error = %v", err)
+	}
+	if got, _ := dst.All(ctx); len(got) != 4 {
+		t.Errorf("dst.All() after merging = %v, want 4 ranges", got)
+	}
+
+	if err := dst.ImportJSON(ctx, bytes.NewBufferString(exported), false); err != nil {
+		t.Fatalf("dst.ImportJSON() error = %v", err)
+	}
+	want, _ := src.All(ctx)
+	got, _ := dst.All(ctx)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dst.All() = %v, want %v", got, want)
+	}
+
+	invalid := []string{
+		`{"low": "10.0.0.0", "high": "10.0.0.1", "reason": "object"}`,
+		`[{"low": "10.0.0.0", "high": "10.0.0.1", "reason": "valid"}, {"low": "10.0.0.0", "high": "invalid", "reason": "invalid"}]`,
+		`[{"low": "10.0.0.0", "high": 1, "reason": "wrong type"}]`,
+		`[{"low": "10.0.0.0", "high": "10.0.0.1", "reason": "unterminated"}`,
+	}
+	for _, data := range invalid {
+		if err := dst.ImportJSON(ctx, bytes.NewBufferString(data), false); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("dst.ImportJSON(%s) error = %v, want %v", data, err, ErrInvalidRange)
+		}
+	}
+	if got, _ := dst.All(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("dst.All() after invalid imports = %v, want %v", got, want)
+	}
+}
+
+func TestClient_ExportImportCSV(t *testing.T) {
+	src := initRDB(0)
+	defer src.Close()
+
+	dst := initRDB(1)
+	defer dst.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first, with a comma"},
+		{"10.0.1.5", ""},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := src.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("src.Insert() error = %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportCSV(ctx, &buf); err != nil {
+		t.Fatalf("src.ExportCSV() error = %v", err)
+	}
+	want := "low,high,reason\n10.0.0.0,10.0.0.255,\"first, with a comma\"\n10.0.1.5,10.0.1.5,\n10.0.2.0,10.0.2.10,third\n"
+	if buf.String() != want {
+		t.Fatalf("src.ExportCSV() = %q, want %q", buf.String(), want)
+	}
+
+	wantRanges, _ := src.All(ctx)
+	for _, data := range []string{want, strings.TrimPrefix(want, "low,high,reason\n")} {
+		if err := dst.ImportCSV(ctx, strings.NewReader(data), false); err != nil {
+			t.Fatalf("dst.ImportCSV() error = %v", err)
+		}
+		if got, _ := dst.All(ctx); !reflect.DeepEqual(got, wantRanges) {
+			t.Errorf("dst.All() = %v, want %v", got, wantRanges)
+		}
+	}
+
+	invalid := []string{
+		"10.0.0.0,10.0.0.1\n",
+		"10.0.0.0,10.0.0.1,valid\n10.0.0.0,invalid,invalid\n",
+		"low,high,reason\nlow,high,reason\n",
+	}
+	for _, data := range invalid {
+		if err := dst.ImportCSV(ctx, strings.NewReader(data), false); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("dst.ImportCSV(%q) error = %v, want %v", data, err, ErrInvalidRange)
+		}
+	}
+}
+
+func TestImportTextFile(t *testing.T) {
+	data := `# blocklist
+10.0.0.0/24 # first network
+
+  10.0.1.5  
+10.0.2.0 - 10.0.2.10
+#10.0.3.0/24
+`
+	got, err := ImportTextFile(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ImportTextFile() error = %v", err)
+	}
+	want := []RangeEntry{
+		{Range: "10.0.0.0/24"},
+		{Range: "10.0.1.5"},
+		{Range: "10.0.2.0 - 10.0.2.10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ImportTextFile() = %v, want %v", got, want)
+	}
+
+	invalid := "10.0.0.0/24\ninvalid\n10.0.1.5\n10.0.2.10 - 10.0.2.0 # reversed\n"
+	got, err = ImportTextFile(strings.NewReader(invalid))
+	if !errors.Is(err, ErrInvalidRange) || got != nil {
+		t.Fatalf("ImportTextFile() = %v, %v, want nil, %v", got, err, ErrInvalidRange)
+	}
+	for _, line := range []string{"line 2:", "line 4:"} {
+		if !strings.Contains(err.Error(), line) {
+			t.Errorf("ImportTextFile() error = %v, want it to contain %q", err, line)
+		}
+	}
+}
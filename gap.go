@@ -0,0 +1,105 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+)
+
+// LargestGap returns the range with the most IPs that is not covered by any stored range.
+// The Reason of the returned range is empty. In case multiple gaps have the same size, the lowest one is returned.
+// Gaps are limited to the IPv4 address space that lies within the global boundaries of the client.
+// ErrNoResult is returned if there is no gap at all.
+func (c *Client) LargestGap(ctx context.Context) (RangeInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return RangeInfo{}, err
+	}
+
+	first, last := int64(0), int64(math.MaxUint32)
+	if !math.IsInf(c.minScore, -1) && int64(c.minScore)+1 > first {
+		first = int64(c.minScore) + 1
+	}
+	if !math.IsInf(c.maxScore, 1) && int64(c.maxScore)-1 < last {
+		last = int64(c.maxScore) - 1
+	}
+
+	var (
+		found           bool
+		gapLow, gapHigh int64
+	)
+	consider := func(low, high int64) {
+		if low > high {
+			return
+		}
+		if !found || high-low > gapHigh-gapLow {
+			found = true
+			gapLow, gapHigh = low, high
+		}
+	}
+
+	next := first
+	for _, r := range ranges {
+		consider(next, ipToInt64(r.Low)-1)
+		next = ipToInt64(r.High) + 1
+	}
+	consider(next, last)
+
+	if !found {
+		return RangeInfo{}, fmt.Errorf("%w : the whole address space is covered", ErrNoResult)
+	}
+
+	return RangeInfo{
+		Low:  ipFromInt64(gapLow),
+		High: ipFromInt64(gapHigh),
+	}, nil
+}
+
+// GapBetween returns the first and last IP that lie between the two passed ranges, which must be stored as is.
+// The order of the ranges does not matter. nil IPs are returned if the ranges overlap or are adjacent to each other.
+// ErrIPNotFound is returned if either of the ranges is not stored and an *OverlapError, which wraps ErrRangeOverlap,
+// if other stored ranges lie in between, meaning that the IPs between both ranges are not uncovered.
+func (c *Client) GapBetween(ctx context.Context, rangeA, rangeB string) (low, high net.IP, err error) {
+	lowA, highA, err := parseRange(rangeA, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	lowB, highB, err := parseRange(rangeB, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, r := range []parsedRange{{lowA, highA}, {lowB, highB}} {
+		_, _, ok, err := c.exactRange(ctx, r.low, r.high)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("%w : %s - %s is not stored", ErrIPNotFound, r.low.IP, r.high.IP)
+		}
+	}
+
+	if lowB.Int64 < lowA.Int64 {
+		lowA, highA, lowB, highB = lowB, highB, lowA, highA
+	}
+	if highA.Int64+1 >= lowB.Int64 {
+		return nil, nil, nil
+	}
+
+	gapLow, gapHigh := highA.Above(), lowB.Below()
+	between, err := c.overlapping(ctx, gapLow, gapHigh)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(between) > 0 {
+		return nil, nil, &OverlapError{Ranges: between}
+	}
+	return gapLow.IP, gapHigh.IP, nil
+}
@@ -0,0 +1,90 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClient_LargestGap(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	tests := []struct {
+		insert   string
+		wantLow  string
+		wantHigh string
+	}{
+		{"", "0.0.0.0", "255.255.255.255"},
+		{"128.0.0.0/2", "0.0.0.0", "127.255.255.255"},
+		{"0.0.0.0/2", "64.0.0.0", "127.255.255.255"},
+		{"64.0.0.0/3", "192.0.0.0", "255.255.255.255"},
+		{"192.0.0.0 - 254.255.255.255", "96.0.0.0", "127.255.255.255"},
+		{"96.0.0.0/3", "255.0.0.0", "255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		if tt.insert != "" {
+			if err := rdb.Insert(ctx, tt.insert, "reason"); err != nil {
+				t.Fatalf("rdb.Insert() error = %v", err)
+			}
+		}
+
+		got, err := rdb.LargestGap(ctx)
+		if err != nil {
+			t.Fatalf("rdb.LargestGap() error = %v", err)
+		}
+		if got.Low.String() != tt.wantLow || got.High.String() != tt.wantHigh || got.Reason != "" {
+			t.Errorf("rdb.LargestGap() = %s - %s, want %s - %s", got.Low, got.High, tt.wantLow, tt.wantHigh)
+		}
+	}
+}
+
+func TestClient_GapBetween(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "first"},
+		{"10.0.0.10 - 10.0.0.19", "second"},
+		{"10.0.0.30", "third"},
+		{"10.0.1.0/24", "fourth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		rangeA, rangeB    string
+		wantLow, wantHigh string
+		wantErr           error
+	}{
+		{"10.0.0.10 - 10.0.0.19", "10.0.0.30", "10.0.0.20", "10.0.0.29", nil},
+		{"10.0.0.30", "10.0.0.10 - 10.0.0.19", "10.0.0.20", "10.0.0.29", nil},
+		{"10.0.0.30", "10.0.1.0/24", "10.0.0.31", "10.0.0.255", nil},
+		{"10.0.0.0 - 10.0.0.9", "10.0.0.10 - 10.0.0.19", "", "", nil},
+		{"10.0.0.30", "10.0.0.30", "", "", nil},
+		{"10.0.0.0 - 10.0.0.9", "10.0.0.30", "", "", ErrRangeOverlap},
+		{"10.0.0.0 - 10.0.0.19", "10.0.0.30", "", "", ErrIPNotFound},
+		{"10.0.0.30", "10.0.2.0/24", "", "", ErrIPNotFound},
+	}
+	for _, tt := range tests {
+		low, high, err := rdb.GapBetween(ctx, tt.rangeA, tt.rangeB)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.GapBetween(%s, %s) error = %v, want %v", tt.rangeA, tt.rangeB, err, tt.wantErr)
+			continue
+		}
+
+		gotLow, gotHigh := "", ""
+		if low != nil || high != nil {
+			gotLow, gotHigh = low.String(), high.String()
+		}
+		if gotLow != tt.wantLow || gotHigh != tt.wantHigh {
+			t.Errorf("rdb.GapBetween(%s, %s) = %s - %s, want %s - %s", tt.rangeA, tt.rangeB, gotLow, gotHigh, tt.wantLow, tt.wantHigh)
+		}
+	}
+}
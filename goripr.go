@@ -1,9 +1,25 @@
 package goripr
 
+import (
+	"fmt"
+	"strings"
+)
+
 var (
 
+	// All keys share the hash tag {goripr}, which places them in the same hash slot of a redis cluster.
+	// This is required, as the transactions access the sorted set and the boundary hashes at once,
+	// and means that all of the ranges must fit on a single shard.
+
 	// IPRangesKey contains the key name of the sorted set that contains the IPs (integers)
-	IPRangesKey = "________________IP_RANGES________________"
+	IPRangesKey = "{goripr}:ranges"
+
+	// BoundaryKeyPrefix prefixes the IPs of the boundaries in the key names of their attribute hashes.
+	BoundaryKeyPrefix = "{goripr}:"
+
+	// InsertionOrderKey contains the key name of the list that records the first IP of every inserted range
+	// if the client was created WithInsertionOrder.
+	InsertionOrderKey = "{goripr}:insertion-order"
 
 	// DeleteReason is given to a specific deltion range
 	// on a second attept (not atomic) the range is then finally deleted.
@@ -22,7 +38,16 @@ const (
 	ErrDatabaseInconsistent = Error("the databe is in an inconsistent state")
 
 	// ErrInvalidRange is returned when a passed string is not a valid range
-	ErrInvalidRange = Error("invalid range passed, use either of these: <IP>, <IP>/<1-32>, <IP> - <IP>")
+	ErrInvalidRange = Error("invalid range passed, use either of these: <IP>, <IP>/<1-32>, <IP> - <IP>, <IP>...<exclusive IP>")
+
+	// ErrInvalidOptions is returned when the textual representation of the Options cannot be parsed.
+	ErrInvalidOptions = Error("invalid options passed, use Key=Value lines")
+
+	// ErrInvalidCursor is returned when a passed pagination cursor was not returned by ListAllPage.
+	ErrInvalidCursor = Error("invalid cursor passed")
+
+	// ErrInvalidTTL is returned when a passed time to live is not positive.
+	ErrInvalidTTL = Error("invalid TTL passed, must be positive")
 
 	// ErrIPv6NotSupported is returned if an IPv6 range or IP input is detected.
 	ErrIPv6NotSupported = Error("IPv6 ranges are not supported")
@@ -33,11 +58,43 @@ const (
 	// ErrNoResult is returned when a result slic is empty or some connection error occurs during retrieval of values.
 	ErrNoResult = Error("could not retrieve any results from the database")
 
+	// ErrRangeOverlap is returned when a range must not overlap any stored range, but does.
+	ErrRangeOverlap = Error("the range overlaps stored ranges")
+
+	// ErrKeyDeleted is returned when the key watcher noticed that the sorted set of the ranges was deleted externally.
+	ErrKeyDeleted = Error("the ranges were deleted externally")
+
 	// ErrIPNotFound is returned if the passed IP is not contained in any ranges
 	ErrIPNotFound = Error("the given IP was not found in any database ranges")
+
+	// ErrSubnetTruncated is returned together with the first MaxSubnetIPs IPs of a subnet
+	// when the subnet contains even more of the requested IPs.
+	ErrSubnetTruncated = Error("the subnet contains more IPs than MaxSubnetIPs, the result is truncated")
+
+	// ErrRangeExpired is returned when the range that contains the looked up IP has expired,
+	// but its boundaries have not been removed by SweepExpired, yet.
+	ErrRangeExpired = Error("the range has expired")
 )
 
 // Error is a wrapper for constant errors that are not supposed to be changed.
 type Error string
 
 func (e Error) Error() string { return string(e) }
+
+// OverlapError contains the stored ranges that overlap a range, which must not overlap any stored range.
+type OverlapError struct {
+	Ranges []RangeInfo
+}
+
+func (e *OverlapError) Error() string {
+	ranges := make([]string, 0, len(e.Ranges))
+	for _, r := range e.Ranges {
+		ranges = append(ranges, fmt.Sprintf("%s - %s", r.Low, r.High))
+	}
+	return fmt.Sprintf("%v : %s", ErrRangeOverlap, strings.Join(ranges, ", "))
+}
+
+// Unwrap allows to check the error with errors.Is(err, ErrRangeOverlap).
+func (e *OverlapError) Unwrap() error {
+	return ErrRangeOverlap
+}
@@ -0,0 +1,162 @@
+// Package goriprtest provides test doubles for the goripr package, which allow to test code
+// that depends on a goripr.RangeStore without a redis server.
+package goriprtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jxsl13/goripr/v2"
+)
+
+var _ goripr.RangeStore = (*MockRangeStore)(nil)
+
+// Call is a single recorded method call of a MockRangeStore.
+type Call struct {
+	Method string
+	// Args contains all arguments of the call except for the context.
+	Args []interface{}
+}
+
+// MockRangeStore records all method calls and returns the results of the corresponding Func fields.
+// Methods whose Func field is nil return zero values. It is safe for concurrent use as long as
+// the Func fields are not modified concurrently.
+//
+//	m := &goriprtest.MockRangeStore{
+//		FindFunc: func(ctx context.Context, ip string) (string, error) {
+//			return "", goripr.ErrIPNotFound
+//		},
+//	}
+type MockRangeStore struct {
+	InsertFunc         func(ctx context.Context, ipRange, reason string) error
+	RemoveFunc         func(ctx context.Context, ipRange string) error
+	FindFunc           func(ctx context.Context, ip string) (string, error)
+	FindManyFunc       func(ctx context.Context, ips []string) (map[string]string, error)
+	UpdateReasonOfFunc func(ctx context.Context, ip string, fn goripr.UpdateFunc) error
+	InsertBatchFunc    func(ctx context.Context, entries []goripr.RangeEntry) error
+	RemoveBatchFunc    func(ctx context.Context, ranges []string) error
+	AllFunc            func(ctx context.Context) ([]goripr.RangeInfo, error)
+	FlushFunc          func(ctx context.Context) error
+	ResetFunc          func(ctx context.Context) error
+	CloseFunc          func() error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns all recorded calls in the order in which they were made.
+func (m *MockRangeStore) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsOf returns all recorded calls of the passed method.
+func (m *MockRangeStore) CallsOf(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	calls := make([]Call, 0)
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (m *MockRangeStore) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func (m *MockRangeStore) Insert(ctx context.Context, ipRange, reason string) error {
+	m.record("Insert", ipRange, reason)
+	if m.InsertFunc == nil {
+		return nil
+	}
+	return m.InsertFunc(ctx, ipRange, reason)
+}
+
+func (m *MockRangeStore) Remove(ctx context.Context, ipRange string) error {
+	m.record("Remove", ipRange)
+	if m.RemoveFunc == nil {
+		return nil
+	}
+	return m.RemoveFunc(ctx, ipRange)
+}
+
+func (m *MockRangeStore) Find(ctx context.Context, ip string) (string, error) {
+	m.record("Find", ip)
+	if m.FindFunc == nil {
+		return "", nil
+	}
+	return m.FindFunc(ctx, ip)
+}
+
+func (m *MockRangeStore) FindMany(ctx context.Context, ips []string) (map[string]string, error) {
+	m.record("FindMany", ips)
+	if m.FindManyFunc == nil {
+		return nil, nil
+	}
+	return m.FindManyFunc(ctx, ips)
+}
+
+func (m *MockRangeStore) UpdateReasonOf(ctx context.Context, ip string, fn goripr.UpdateFunc) error {
+	m.record("UpdateReasonOf", ip, fn)
+	if m.UpdateReasonOfFunc == nil {
+		return nil
+	}
+	return m.UpdateReasonOfFunc(ctx, ip, fn)
+}
+
+func (m *MockRangeStore) InsertBatch(ctx context.Context, entries []goripr.RangeEntry) error {
+	m.record("InsertBatch", entries)
+	if m.InsertBatchFunc == nil {
+		return nil
+	}
+	return m.InsertBatchFunc(ctx, entries)
+}
+
+func (m *MockRangeStore) RemoveBatch(ctx context.Context, ranges []string) error {
+	m.record("RemoveBatch", ranges)
+	if m.RemoveBatchFunc == nil {
+		return nil
+	}
+	return m.RemoveBatchFunc(ctx, ranges)
+}
+
+func (m *MockRangeStore) All(ctx context.Context) ([]goripr.RangeInfo, error) {
+	m.record("All")
+	if m.AllFunc == nil {
+		return nil, nil
+	}
+	return m.AllFunc(ctx)
+}
+
+func (m *MockRangeStore) Flush(ctx context.Context) error {
+	m.record("Flush")
+	if m.FlushFunc == nil {
+		return nil
+	}
+	return m.FlushFunc(ctx)
+}
+
+func (m *MockRangeStore) Reset(ctx context.Context) error {
+	m.record("Reset")
+	if m.ResetFunc == nil {
+		return nil
+	}
+	return m.ResetFunc(ctx)
+}
+
+func (m *MockRangeStore) Close() error {
+	m.record("Close")
+	if m.CloseFunc == nil {
+		return nil
+	}
+	return m.CloseFunc()
+}
@@ -0,0 +1,63 @@
+package goriprtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jxsl13/goripr/v2"
+)
+
+// blocked is an example of code that depends on a RangeStore.
+func blocked(ctx context.Context, store goripr.RangeStore, ip string) (bool, error) {
+	_, err := store.Find(ctx, ip)
+	if errors.Is(err, goripr.ErrIPNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func TestMockRangeStore(t *testing.T) {
+	ctx := context.TODO()
+	m := &MockRangeStore{
+		FindFunc: func(ctx context.Context, ip string) (string, error) {
+			if ip == "10.0.0.1" {
+				return "blocked", nil
+			}
+			return "", goripr.ErrIPNotFound
+		},
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.0.0.1", true},
+		{"10.0.0.2", false},
+	}
+	for _, tt := range tests {
+		got, err := blocked(ctx, m, tt.ip)
+		if err != nil || got != tt.want {
+			t.Errorf("blocked(%s) = %t, %v, want %t", tt.ip, got, err, tt.want)
+		}
+	}
+
+	if err := m.Insert(ctx, "10.0.1.0/24", "reason"); err != nil {
+		t.Errorf("m.Insert() error = %v, want nil by default", err)
+	}
+
+	wantCalls := []Call{
+		{Method: "Find", Args: []interface{}{"10.0.0.1"}},
+		{Method: "Find", Args: []interface{}{"10.0.0.2"}},
+		{Method: "Insert", Args: []interface{}{"10.0.1.0/24", "reason"}},
+	}
+	if got := m.Calls(); !reflect.DeepEqual(got, wantCalls) {
+		t.Errorf("m.Calls() = %v, want %v", got, wantCalls)
+	}
+	if got := m.CallsOf("Find"); len(got) != 2 {
+		t.Errorf("m.CallsOf(Find) = %v, want 2 calls", got)
+	}
+}
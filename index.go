@@ -0,0 +1,119 @@
+package goripr
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// indexRecordSize is the size of a single range record within the index data:
+// first IP, last IP, reason offset and reason length, each encoded as big endian uint32.
+const indexRecordSize = 16
+
+// Index is an immutable in-memory snapshot of all stored ranges that can be searched
+// without any locking or database round trips.
+// All ranges are stored in a single flat byte slice, the sorted range records followed by the reasons.
+type Index struct {
+	data []byte
+	n    int
+}
+
+// BuildIndex creates an immutable snapshot of all stored ranges.
+func (c *Client) BuildIndex(ctx context.Context) (*Index, error) {
+	c.mu.RLock()
+	ranges, err := c.listAll(ctx)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	size := len(ranges) * indexRecordSize
+	for _, r := range ranges {
+		size += len(r.Reason)
+	}
+
+	data := make([]byte, len(ranges)*indexRecordSize, size)
+	offset := uint32(0)
+	for idx, r := range ranges {
+		record := data[idx*indexRecordSize : (idx+1)*indexRecordSize]
+		binary.BigEndian.PutUint32(record[0:4], uint32(ipToInt64(r.Low)))
+		binary.BigEndian.PutUint32(record[4:8], uint32(ipToInt64(r.High)))
+		binary.BigEndian.PutUint32(record[8:12], offset)
+		binary.BigEndian.PutUint32(record[12:16], uint32(len(r.Reason)))
+
+		data = append(data, r.Reason...)
+		offset += uint32(len(r.Reason))
+	}
+
+	return &Index{
+		data: data,
+		n:    len(ranges),
+	}, nil
+}
+
+// Len returns the number of ranges within the index.
+func (idx *Index) Len() int {
+	return idx.n
+}
+
+// Find searches for the range that contains ip and returns its reason.
+// The returned bool is false if ip is not contained in any range or is not an IPv4 address.
+func (idx *Index) Find(ip net.IP) (string, bool) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return "", false
+	}
+	value := binary.BigEndian.Uint32(ip4)
+
+	// first range whose last IP is not below value
+	i := sort.Search(idx.n, func(i int) bool {
+		return idx.uint32At(i, 4) >= value
+	})
+	if i == idx.n || idx.uint32At(i, 0) > value {
+		return "", false
+	}
+
+	reasons := idx.data[idx.n*indexRecordSize:]
+	offset := idx.uint32At(i, 8)
+	length := idx.uint32At(i, 12)
+	return string(reasons[offset : offset+length]), true
+}
+
+// uint32At returns the field at the byte offset field of the i-th record.
+func (idx *Index) uint32At(i, field int) uint32 {
+	start := i*indexRecordSize + field
+	return binary.BigEndian.Uint32(idx.data[start : start+4])
+}
+
+// RefreshIndex builds a new index every interval and stores it in p until ctx is done.
+// The first index is built and stored immediately. It blocks and is supposed to be run in its own goroutine,
+// while readers use p.Load().Find(ip) without any locking.
+// An interval < 1 defaults to one minute.
+// ctx.Err() is returned when ctx is done, any other error aborts the refreshing.
+func (c *Client) RefreshIndex(ctx context.Context, p *atomic.Pointer[Index], interval time.Duration) error {
+	if interval < 1 {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		idx, err := c.BuildIndex(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		} else if err != nil {
+			return err
+		}
+		p.Store(idx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
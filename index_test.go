@@ -0,0 +1,113 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_BuildIndex(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	for _, r := range ranges {
+		if err := rdb.Insert(ctx, r.Range, r.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	idx, err := rdb.BuildIndex(ctx)
+	if err != nil {
+		t.Fatalf("rdb.BuildIndex() error = %v", err)
+	}
+
+	all, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	if idx.Len() != len(all) {
+		t.Fatalf("idx.Len() = %d, want %d", idx.Len(), len(all))
+	}
+
+	for _, r := range all {
+		low, high := ipToInt64(r.Low), ipToInt64(r.High)
+		for _, ip := range []int64{low - 1, low, low + (high-low)/2, high, high + 1} {
+			if ip < 0 || ip >= math.MaxUint32 {
+				continue
+			}
+			want, err := rdb.Find(ctx, ipFromInt64(ip).String())
+			wantOK := err == nil
+			if err != nil && !errors.Is(err, ErrIPNotFound) {
+				t.Fatalf("rdb.Find() error = %v", err)
+			}
+
+			got, ok := idx.Find(ipFromInt64(ip))
+			if got != want || ok != wantOK {
+				t.Errorf("idx.Find(%s) = %q, %v, want %q, %v", ipFromInt64(ip), got, ok, want, wantOK)
+			}
+		}
+	}
+
+	if _, ok := idx.Find(net.ParseIP("::1")); ok {
+		t.Errorf("idx.Find(::1) found an IPv6 address")
+	}
+}
+
+func TestClient_BuildIndexEmpty(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	// the global boundaries have no IPs and must not be indexed
+	idx, err := rdb.BuildIndex(context.TODO())
+	if err != nil {
+		t.Fatalf("rdb.BuildIndex() error = %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("idx.Len() = %d, want 0", idx.Len())
+	}
+	for _, ip := range []string{"0.0.0.0", "10.0.0.1", "255.255.255.255"} {
+		if reason, ok := idx.Find(net.ParseIP(ip)); ok {
+			t.Errorf("idx.Find(%s) = %q, want no range", ip, reason)
+		}
+	}
+}
+
+func TestClient_RefreshIndex(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	var p atomic.Pointer[Index]
+	done := make(chan error, 1)
+	go func() {
+		done <- rdb.RefreshIndex(ctx, &p, 10*time.Millisecond)
+	}()
+
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "refreshed"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	err := rdb.WaitForCondition(ctx, func(*Client) (bool, error) {
+		idx := p.Load()
+		if idx == nil {
+			return false, nil
+		}
+		reason, ok := idx.Find(net.ParseIP("10.0.0.1"))
+		return ok && reason == "refreshed", nil
+	}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("index was not refreshed: %v", err)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("rdb.RefreshIndex() error = %v, want %v", err, context.Canceled)
+	}
+}
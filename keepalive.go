@@ -0,0 +1,53 @@
+package goripr
+
+import (
+	"context"
+	"time"
+)
+
+// WithKeepalive sends a PING command every interval in order to keep idle connections from being dropped
+// by load balancers or NAT gateways. Every PING uses a single connection of the pool, which is usually the
+// most recently used one. The background goroutine exits when the Client is closed.
+// An interval <= 0 disables the keepalive.
+func WithKeepalive(interval time.Duration) Option {
+	return func(c *Client) {
+		c.keepaliveInterval = interval
+	}
+}
+
+// startKeepalive starts the goroutine that pings the database every keepaliveInterval.
+func (c *Client) startKeepalive() {
+	c.keepaliveStop = make(chan struct{})
+	c.keepaliveDone = make(chan struct{})
+
+	go func(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				// a failing ping is retried at the next tick, commands report connection errors on their own
+				_ = c.rdb.Ping(ctx).Err()
+				cancel()
+			}
+		}
+	}(c.keepaliveInterval, c.keepaliveStop, c.keepaliveDone)
+}
+
+// stopKeepalive stops the keepalive goroutine and waits for it to exit.
+func (c *Client) stopKeepalive() {
+	if c.keepaliveStop == nil {
+		return
+	}
+
+	c.keepaliveOnce.Do(func() {
+		close(c.keepaliveStop)
+	})
+	<-c.keepaliveDone
+}
@@ -0,0 +1,36 @@
+package goripr
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithKeepalive(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	rdb, err := NewClient(context.TODO(), Options{Addr: redisAddr}, WithDebugLogging(logger), WithKeepalive(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if err := rdb.Close(); err != nil {
+		t.Fatalf("rdb.Close() error = %v", err)
+	}
+
+	// the initial ping of NewClient is logged as well
+	pings := strings.Count(buf.String(), "cmd=ping")
+	if pings < 3 {
+		t.Errorf("logged %d pings, want at least 3", pings)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := strings.Count(buf.String(), "cmd=ping"); got != pings {
+		t.Errorf("logged %d pings after Close, want %d", got, pings)
+	}
+}
@@ -0,0 +1,480 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xgfone/go-netaddr"
+)
+
+// RangesAtBoundary returns the stored ranges that have the passed IP as either their first or last IP.
+// As stored ranges never overlap, the IP can be the boundary of at most one range, which is either
+// a range that starts at the IP, a range that ends at the IP or a single IP range.
+// ErrIPNotFound is returned if the IP is not a boundary of any range.
+func (c *Client) RangesAtBoundary(ctx context.Context, ip string) ([]RangeInfo, error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", true, true)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	err = c.rdb.ZScore(ctx, IPRangesKey, bnd.ID).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrIPNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	r, err := c.containingRange(ctx, bnd)
+	if err != nil {
+		return nil, err
+	}
+	return []RangeInfo{r}, nil
+}
+
+// BoundaryType describes which boundary of a range an IP is.
+type BoundaryType int
+
+const (
+	// NotABoundary is the type of IPs without stored attributes.
+	NotABoundary BoundaryType = iota
+	// Lower is the type of the first IP of a range.
+	Lower
+	// Upper is the type of the last IP of a range.
+	Upper
+	// Double is the type of single IP ranges, which are the first and the last IP at once.
+	Double
+)
+
+// String returns the name of the boundary type.
+func (t BoundaryType) String() string {
+	switch t {
+	case Lower:
+		return "Lower"
+	case Upper:
+		return "Upper"
+	case Double:
+		return "Double"
+	}
+	return "NotABoundary"
+}
+
+// GetBoundaryReason returns the reason and type that are stored in the attributes of the passed IP.
+// In contrast to Find, the attributes are read directly without looking at the vicinity of the IP,
+// which is why IPs within a range are NotABoundary and the sorted set is not checked at all.
+// This is meant for debugging inconsistencies of single boundaries.
+func (c *Client) GetBoundaryReason(ctx context.Context, ip string) (reason string, boundaryType BoundaryType, err error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return "", NotABoundary, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", false, false)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	attrs, err := c.rdb.HMGet(ctx, bnd.Key(), boundaryFields...).Result()
+	if err != nil {
+		return "", NotABoundary, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	err = bnd.SetAttributes(attrs)
+	if err != nil {
+		return "", NotABoundary, fmt.Errorf("%w : %v", ErrDatabaseInconsistent, err)
+	}
+
+	return bnd.Reason, boundaryTypeOf(bnd), nil
+}
+
+// boundaryTypeOf returns the type of the boundary based on its attributes.
+func boundaryTypeOf(b boundary) BoundaryType {
+	switch {
+	case b.LowerBound && b.UpperBound:
+		return Double
+	case b.LowerBound:
+		return Lower
+	case b.UpperBound:
+		return Upper
+	}
+	return NotABoundary
+}
+
+// GlobalBoundary is the stored state of the -inf or the +inf global boundary.
+type GlobalBoundary struct {
+	// Score is the score of the boundary in the sorted set, which is ±inf
+	// unless the Client was created WithCustomBoundaries.
+	Score  float64
+	Type   BoundaryType
+	Reason string
+}
+
+// GlobalBoundaries returns the stored state of the -inf and +inf global boundaries, which helps to debug them.
+// Intact global boundaries are an Upper -inf boundary and a Lower +inf boundary with the reasons "-inf" and "+inf".
+// ErrKeyDeleted is returned if a global boundary is missing in the sorted set.
+func (c *Client) GlobalBoundaries(ctx context.Context) (negInf, posInf GlobalBoundary, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bnds := []boundary{negInfBoundary, posInfBoundary}
+	scoreCmds := make([]*redis.FloatCmd, len(bnds))
+	attrCmds := make([]*redis.SliceCmd, len(bnds))
+
+	tx := c.rdb.TxPipeline()
+	for idx := range bnds {
+		scoreCmds[idx] = tx.ZScore(ctx, IPRangesKey, bnds[idx].ID)
+		attrCmds[idx] = bnds[idx].Get(ctx, tx)
+	}
+	_, err = tx.Exec(ctx)
+	if errors.Is(err, redis.Nil) {
+		return negInf, posInf, ErrKeyDeleted
+	} else if err != nil {
+		return negInf, posInf, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	result := make([]GlobalBoundary, len(bnds))
+	for idx := range bnds {
+		err = bnds[idx].SetAttributes(attrCmds[idx].Val())
+		if err != nil {
+			return negInf, posInf, fmt.Errorf("%w : %v", ErrDatabaseInconsistent, err)
+		}
+
+		result[idx] = GlobalBoundary{
+			Score:  scoreCmds[idx].Val(),
+			Type:   boundaryTypeOf(bnds[idx]),
+			Reason: bnds[idx].Reason,
+		}
+	}
+	return result[0], result[1], nil
+}
+
+// NextBoundary returns the first stored boundary above the passed IP without looking up the range that it belongs to.
+// The boundary is returned as a RangeInfo whose Low and High are the IP of the boundary.
+// isSentinel is true if there is no boundary of a range above the IP and the +inf boundary is returned,
+// which has neither a Low nor a High IP.
+// This provides lower level access for custom traversals of the sorted set.
+func (c *Client) NextBoundary(ctx context.Context, ip string) (boundary RangeInfo, isSentinel bool, err error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return RangeInfo{}, false, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", false, false)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.nearestBoundary(ctx, bnd.Float64+1, math.Inf(1), false)
+}
+
+// PrevBoundary returns the first stored boundary below the passed IP like NextBoundary.
+// isSentinel is true if there is no boundary of a range below the IP and the -inf boundary is returned.
+// Together with NextBoundary, this allows to traverse the boundaries in both directions.
+func (c *Client) PrevBoundary(ctx context.Context, ip string) (boundary RangeInfo, isSentinel bool, err error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return RangeInfo{}, false, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", false, false)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.nearestBoundary(ctx, math.Inf(-1), bnd.Float64-1, true)
+}
+
+// nearestBoundary returns the first stored boundary within the inclusive score interval [min, max],
+// which is the boundary with the lowest score or the one with the highest score if reverse is true.
+func (c *Client) nearestBoundary(ctx context.Context, min, max float64, reverse bool) (RangeInfo, bool, error) {
+	if err := c.checkKey(); err != nil {
+		return RangeInfo{}, false, err
+	}
+
+	bnds, err := c.nearestStored(ctx, min, max, reverse, 1)
+	if err != nil {
+		return RangeInfo{}, false, err
+	}
+	if len(bnds) == 0 {
+		// the ±inf boundaries are always stored
+		return RangeInfo{}, false, ErrDatabaseInconsistent
+	}
+
+	bnd := bnds[0]
+	if bnd.IsInfBound() {
+		return RangeInfo{Reason: bnd.Reason}, true, nil
+	}
+	return RangeInfo{Low: bnd.IP, High: bnd.IP, Reason: bnd.Reason}, false, nil
+}
+
+// FindAll returns every stored range that contains the passed IP.
+// Overlapping inserts are cut or merged when they are inserted, which is why the stored ranges never
+// overlap and at most a single range is returned.
+// ErrIPNotFound is returned if the IP is not contained in any range.
+func (c *Client) FindAll(ctx context.Context, ip string) ([]RangeInfo, error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", true, true)
+
+	if !c.inBounds(bnd) {
+		return nil, ErrIPNotFound
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	r, err := c.containingRange(ctx, bnd)
+	if err != nil {
+		return nil, err
+	}
+	return []RangeInfo{r}, nil
+}
+
+// containingRange returns the stored range that contains the IP of bnd.
+func (c *Client) containingRange(ctx context.Context, bnd boundary) (RangeInfo, error) {
+	below, inside, above, expired, err := c.expiringVicinity(ctx, bnd, bnd, 1)
+	if err != nil {
+		return RangeInfo{}, err
+	}
+
+	r, err := containingWithin(below, inside, above)
+	if errors.Is(err, ErrIPNotFound) && expired {
+		return RangeInfo{}, ErrRangeExpired
+	}
+	return r, err
+}
+
+// containingWithin returns the range that contains the IP whose vicinity is passed.
+func containingWithin(below, inside, above []boundary) (RangeInfo, error) {
+	if len(inside) > 1 || len(below) == 0 || len(above) == 0 {
+		return RangeInfo{}, ErrDatabaseInconsistent
+	}
+
+	if len(inside) == 1 {
+		found := inside[0]
+		switch {
+		case found.LowerBound && found.UpperBound:
+			return RangeInfo{Low: found.IP, High: found.IP, Reason: found.Reason}, nil
+		case found.LowerBound && above[0].IsUpperBound():
+			return RangeInfo{Low: found.IP, High: above[0].IP, Reason: found.Reason}, nil
+		case found.UpperBound && below[0].IsLowerBound():
+			return RangeInfo{Low: below[0].IP, High: found.IP, Reason: found.Reason}, nil
+		}
+		return RangeInfo{}, ErrDatabaseInconsistent
+	}
+
+	belowNearest := below[0]
+	aboveNearest := above[0]
+
+	if !belowNearest.IsLowerBound() || !aboveNearest.IsUpperBound() {
+		return RangeInfo{}, ErrIPNotFound
+	}
+
+	if !belowNearest.EqualReason(aboveNearest) {
+		return RangeInfo{}, fmt.Errorf("%w : reasons inconsistent: %s != %s", ErrDatabaseInconsistent, belowNearest.Reason, aboveNearest.Reason)
+	}
+	return RangeInfo{Low: belowNearest.IP, High: aboveNearest.IP, Reason: belowNearest.Reason}, nil
+}
+
+// RangeExists returns true if exactly the passed range is stored, meaning that the first IP of the range
+// is a lower boundary, the last IP is an upper boundary with the same reason and there are no other
+// boundaries in between. A single IP must be stored as a single IP range.
+func (c *Client) RangeExists(ctx context.Context, ipRange string) (bool, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.checkKey(); err != nil {
+		return false, err
+	}
+
+	tx := c.rdb.TxPipeline()
+	lowScore := tx.ZScore(ctx, IPRangesKey, low.ID)
+	highScore := tx.ZScore(ctx, IPRangesKey, high.ID)
+	between := tx.ZCount(ctx, IPRangesKey, "("+low.Int64String(), "("+high.Int64String())
+	lowAttrs := low.Get(ctx, tx)
+	highAttrs := high.Get(ctx, tx)
+
+	_, err = tx.Exec(ctx)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	if lowScore.Err() != nil || highScore.Err() != nil || between.Val() != 0 {
+		return false, nil
+	}
+
+	err = low.SetAttributes(lowAttrs.Val())
+	if err != nil {
+		return false, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	err = high.SetAttributes(highAttrs.Val())
+	if err != nil {
+		return false, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	if low.EqualIP(high) {
+		return low.LowerBound && low.UpperBound, nil
+	}
+	return low.IsLowerBound() && high.IsUpperBound() && low.EqualReason(high), nil
+}
+
+// ExactMatch returns the reason of the passed range if exactly that range is stored.
+// ok is false if the range is not stored as is, e.g. because it is only part of a larger range
+// or other ranges split it.
+func (c *Client) ExactMatch(ctx context.Context, ipRange string) (reason string, ok bool, err error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return "", false, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lowBnd, _, ok, err := c.exactRange(ctx, low, high)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return lowBnd.Reason, true, nil
+}
+
+// exactRange returns the stored boundaries of exactly the range from low to high.
+// ok is false if the range is not stored as is, e.g. because it is only part of a larger range
+// or split into multiple ranges.
+func (c *Client) exactRange(ctx context.Context, low, high boundary) (lowBnd, highBnd boundary, ok bool, err error) {
+	_, inside, _, err := c.vicinity(ctx, low, high, 1)
+	if err != nil {
+		return boundary{}, boundary{}, false, err
+	}
+
+	lowBnd, highBnd, ok = exactBoundaries(low, high, inside)
+	return lowBnd, highBnd, ok, nil
+}
+
+// exactBoundaries returns the boundaries of the range from low to high, if the boundaries
+// inside of the range's vicinity form exactly that range.
+func exactBoundaries(low, high boundary, inside []boundary) (lowBnd, highBnd boundary, ok bool) {
+	switch len(inside) {
+	case 1:
+		bnd := inside[0]
+		if low.EqualIP(high) && bnd.EqualIP(low) && bnd.LowerBound && bnd.UpperBound {
+			return bnd, bnd, true
+		}
+	case 2:
+		lowBnd, highBnd = inside[0], inside[1]
+		if lowBnd.EqualIP(low) && highBnd.EqualIP(high) &&
+			lowBnd.IsLowerBound() && highBnd.IsUpperBound() && lowBnd.EqualReason(highBnd) {
+			return lowBnd, highBnd, true
+		}
+	}
+	return boundary{}, boundary{}, false
+}
+
+// FindOverlapping returns all stored ranges in ascending order that share at least one IP with the passed range,
+// which are the ranges that an Insert of the passed range would cut or overwrite.
+// Partially overlapping ranges are returned as they are stored, without being cut to the passed range.
+// An empty slice is returned if no range overlaps.
+func (c *Client) FindOverlapping(ctx context.Context, ipRange string) ([]RangeInfo, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.overlapping(ctx, low, high)
+}
+
+// FindContaining returns all stored ranges that fully contain the passed range.
+// Stored ranges never overlap, which is why at most a single range is returned.
+// An empty slice is returned if no range contains the passed range.
+func (c *Client) FindContaining(ctx context.Context, ipRange string) ([]RangeInfo, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.overlapping(ctx, low, high)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RangeInfo, 0, 1)
+	for _, r := range ranges {
+		if ipToInt64(r.Low) <= low.Int64 && high.Int64 <= ipToInt64(r.High) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// FindContainedBy returns all stored ranges in ascending order that lie completely within the passed range.
+// Ranges that only partially overlap the passed range are not returned.
+// An empty slice is returned if no range lies within the passed range.
+func (c *Client) FindContainedBy(ctx context.Context, ipRange string) ([]RangeInfo, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
+
+	store := c.newRedisStore(ctx, nil)
+	bnds, err := store.ZRangeByScore(low.Float64, high.Float64, false, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = store.GetBoundaryAttrs(bnds)
+	if err != nil {
+		return nil, err
+	}
+
+	// an upper boundary of a range that starts below the passed range is skipped and
+	// a lower boundary of a range that ends above the passed range stays pending
+	ranges, _ := pairRanges(nil, bnds)
+	return ranges, nil
+}
+
+// overlapping returns all stored ranges that share at least one IP with the range from low to high.
+func (c *Client) overlapping(ctx context.Context, low, high boundary) ([]RangeInfo, error) {
+	below, inside, above, err := c.vicinity(ctx, low, high, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(below) == 0 || len(above) == 0 {
+		return nil, ErrDatabaseInconsistent
+	}
+
+	bnds := make([]boundary, 0, len(inside)+2)
+	if below[0].IsLowerBound() {
+		// range that starts below and reaches into the passed range
+		bnds = append(bnds, below[0])
+	}
+	bnds = append(bnds, inside...)
+	if len(bnds) > 0 && bnds[len(bnds)-1].IsLowerBound() {
+		// range that starts inside and ends above the passed range
+		bnds = append(bnds, above[0])
+	}
+
+	ranges, _ := pairRanges(nil, bnds)
+	return ranges, nil
+}
@@ -0,0 +1,518 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestClient_RangesAtBoundary(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr error
+	}{
+		{"10.0.0.0", "10.0.0.0 - 10.0.0.255 first", nil},
+		{"10.0.0.255", "10.0.0.0 - 10.0.0.255 first", nil},
+		{"10.0.1.5", "10.0.1.5 - 10.0.1.5 second", nil},
+		{"10.0.0.1", "", ErrIPNotFound},
+		{"10.0.1.6", "", ErrIPNotFound},
+		{"invalid", "", ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		got, err := rdb.RangesAtBoundary(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.RangesAtBoundary(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if len(got) != 1 || got[0].Low.String()+" - "+got[0].High.String()+" "+got[0].Reason != tt.want {
+			t.Errorf("rdb.RangesAtBoundary(%s) = %v, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestClient_GetBoundaryReason(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip         string
+		wantReason string
+		wantType   BoundaryType
+		wantErr    error
+	}{
+		{"10.0.0.0", "first", Lower, nil},
+		{"10.0.0.255", "first", Upper, nil},
+		{"10.0.1.5", "second", Double, nil},
+		{"10.0.0.1", "", NotABoundary, nil},
+		{"invalid", "", NotABoundary, ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		reason, boundaryType, err := rdb.GetBoundaryReason(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) || reason != tt.wantReason || boundaryType != tt.wantType {
+			t.Errorf("rdb.GetBoundaryReason(%s) = %q, %v, %v, want %q, %v, %v",
+				tt.ip, reason, boundaryType, err, tt.wantReason, tt.wantType, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_GlobalBoundaries(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "first"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	negInf, posInf, err := rdb.GlobalBoundaries(ctx)
+	if err != nil {
+		t.Fatalf("rdb.GlobalBoundaries() error = %v", err)
+	}
+	if want := (GlobalBoundary{math.Inf(-1), Upper, "-inf"}); negInf != want {
+		t.Errorf("rdb.GlobalBoundaries() -inf = %v, want %v", negInf, want)
+	}
+	if want := (GlobalBoundary{math.Inf(1), Lower, "+inf"}); posInf != want {
+		t.Errorf("rdb.GlobalBoundaries() +inf = %v, want %v", posInf, want)
+	}
+
+	// All only returns the ranges
+	ranges, err := rdb.All(ctx)
+	if err != nil || len(ranges) != 1 || ranges[0].Reason != "first" {
+		t.Errorf("rdb.All() = %v, %v, want the first range", ranges, err)
+	}
+
+	if err := rdb.rdb.ZRem(ctx, IPRangesKey, posInfBoundary.ID).Err(); err != nil {
+		t.Fatalf("ZREM error = %v", err)
+	}
+	if _, _, err := rdb.GlobalBoundaries(ctx); !errors.Is(err, ErrKeyDeleted) {
+		t.Errorf("rdb.GlobalBoundaries() error = %v, want %v", err, ErrKeyDeleted)
+	}
+}
+
+func TestClient_NextBoundary(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip           string
+		want         string
+		wantSentinel bool
+		wantErr      error
+	}{
+		{"9.255.255.255", "10.0.0.0 first", false, nil},
+		{"10.0.0.0", "10.0.0.255 first", false, nil},
+		{"10.0.0.10", "10.0.0.255 first", false, nil},
+		{"10.0.0.255", "10.0.1.5 second", false, nil},
+		{"10.0.1.5", "", true, nil},
+		{"invalid", "", false, ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		got, isSentinel, err := rdb.NextBoundary(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.NextBoundary(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if isSentinel != tt.wantSentinel {
+			t.Errorf("rdb.NextBoundary(%s) isSentinel = %t, want %t", tt.ip, isSentinel, tt.wantSentinel)
+			continue
+		}
+		if isSentinel {
+			if got.Low != nil || got.High != nil {
+				t.Errorf("rdb.NextBoundary(%s) = %v, want sentinel without IPs", tt.ip, got)
+			}
+			continue
+		}
+		if !got.Low.Equal(got.High) || got.Low.String()+" "+got.Reason != tt.want {
+			t.Errorf("rdb.NextBoundary(%s) = %v, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestClient_PrevBoundary(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip           string
+		want         string
+		wantSentinel bool
+		wantErr      error
+	}{
+		{"10.0.0.0", "", true, nil},
+		{"10.0.0.10", "10.0.0.0 first", false, nil},
+		{"10.0.0.255", "10.0.0.0 first", false, nil},
+		{"10.0.1.5", "10.0.0.255 first", false, nil},
+		{"255.255.255.255", "10.0.1.5 second", false, nil},
+		{"invalid", "", false, ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		got, isSentinel, err := rdb.PrevBoundary(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.PrevBoundary(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if isSentinel != tt.wantSentinel {
+			t.Errorf("rdb.PrevBoundary(%s) isSentinel = %t, want %t", tt.ip, isSentinel, tt.wantSentinel)
+			continue
+		}
+		if isSentinel {
+			if got.Low != nil || got.High != nil {
+				t.Errorf("rdb.PrevBoundary(%s) = %v, want sentinel without IPs", tt.ip, got)
+			}
+			continue
+		}
+		if !got.Low.Equal(got.High) || got.Low.String()+" "+got.Reason != tt.want {
+			t.Errorf("rdb.PrevBoundary(%s) = %v, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestClient_FindAll(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.0.10 - 10.0.0.20", "second"},
+		{"10.0.1.5", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr error
+	}{
+		{"10.0.0.0", "10.0.0.0 - 10.0.0.9 first", nil},
+		{"10.0.0.5", "10.0.0.0 - 10.0.0.9 first", nil},
+		{"10.0.0.15", "10.0.0.10 - 10.0.0.20 second", nil},
+		{"10.0.0.21", "10.0.0.21 - 10.0.0.255 first", nil},
+		{"10.0.1.5", "10.0.1.5 - 10.0.1.5 third", nil},
+		{"10.0.1.6", "", ErrIPNotFound},
+		{"invalid", "", ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		got, err := rdb.FindAll(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.FindAll(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if len(got) != 1 || got[0].Low.String()+" - "+got[0].High.String()+" "+got[0].Reason != tt.want {
+			t.Errorf("rdb.FindAll(%s) = %v, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestClient_IPCovered(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "covered"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		want    bool
+		wantErr error
+	}{
+		{"10.0.0.1", true, nil},
+		{"10.0.1.1", false, nil},
+		{"invalid", false, ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		got, err := rdb.IPCovered(ctx, tt.ip)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.IPCovered(%s) = %v, %v, want %v, %v", tt.ip, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_RangeExists(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.0.10 - 10.0.0.20", "second"},
+		{"10.0.1.5", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange string
+		want    bool
+		wantErr error
+	}{
+		{"10.0.0.10 - 10.0.0.20", true, nil},
+		{"10.0.0.0 - 10.0.0.9", true, nil},
+		{"10.0.0.21 - 10.0.0.255", true, nil},
+		{"10.0.1.5", true, nil},
+		{"10.0.1.5/32", true, nil},
+		{"10.0.0.0/24", false, nil},
+		{"10.0.0.9 - 10.0.0.10", false, nil},
+		{"10.0.0.10", false, nil},
+		{"10.0.2.0/24", false, nil},
+		{"invalid", false, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		got, err := rdb.RangeExists(ctx, tt.ipRange)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.RangeExists(%s) = %v, %v, want %v, %v", tt.ipRange, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_ExactMatch(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.0.10 - 10.0.0.20", "second"},
+		{"10.0.1.5", "third"},
+		{"10.0.2.0/24", "fourth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange    string
+		wantReason string
+		wantOK     bool
+		wantErr    error
+	}{
+		{"10.0.0.10 - 10.0.0.20", "second", true, nil},
+		{"10.0.1.5", "third", true, nil},
+		{"10.0.2.0/24", "fourth", true, nil},
+		{"10.0.0.0/24", "", false, nil},
+		{"10.0.2.0/25", "", false, nil},
+		{"10.0.1.4 - 10.0.1.5", "", false, nil},
+		{"invalid", "", false, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		reason, ok, err := rdb.ExactMatch(ctx, tt.ipRange)
+		if reason != tt.wantReason || ok != tt.wantOK || !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.ExactMatch(%s) = %q, %v, %v, want %q, %v, %v", tt.ipRange, reason, ok, err, tt.wantReason, tt.wantOK, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_FindOverlapping(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "first"},
+		{"10.0.0.20 - 10.0.0.29", "second"},
+		{"10.0.0.40", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange string
+		want    []string
+		wantErr error
+	}{
+		{"10.0.0.10 - 10.0.0.19", []string{}, nil},
+		{"10.0.0.5", []string{"10.0.0.0 - 10.0.0.9 first"}, nil},
+		{"10.0.0.9 - 10.0.0.20", []string{"10.0.0.0 - 10.0.0.9 first", "10.0.0.20 - 10.0.0.29 second"}, nil},
+		{"10.0.0.21 - 10.0.0.28", []string{"10.0.0.20 - 10.0.0.29 second"}, nil},
+		{"10.0.0.0/24", []string{"10.0.0.0 - 10.0.0.9 first", "10.0.0.20 - 10.0.0.29 second", "10.0.0.40 - 10.0.0.40 third"}, nil},
+		{"10.0.0.40", []string{"10.0.0.40 - 10.0.0.40 third"}, nil},
+		{"invalid", nil, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		ranges, err := rdb.FindOverlapping(ctx, tt.ipRange)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.FindOverlapping(%s) error = %v, want %v", tt.ipRange, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if ranges == nil {
+			t.Errorf("rdb.FindOverlapping(%s) = nil, want an empty slice", tt.ipRange)
+		}
+
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.FindOverlapping(%s) = %v, want %v", tt.ipRange, got, tt.want)
+		}
+	}
+}
+
+func TestClient_FindContaining(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.0 - 10.0.1.9", "second"},
+		{"10.0.1.10", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange string
+		want    []string
+	}{
+		{"10.0.0.100 - 10.0.0.150", []string{"10.0.0.0 - 10.0.0.255 first"}},
+		{"10.0.0.0/24", []string{"10.0.0.0 - 10.0.0.255 first"}},
+		{"10.0.0.255", []string{"10.0.0.0 - 10.0.0.255 first"}},
+		{"10.0.0.255 - 10.0.1.0", []string{}},
+		{"10.0.1.5 - 10.0.1.10", []string{}},
+		{"10.0.1.10", []string{"10.0.1.10 - 10.0.1.10 third"}},
+		{"10.0.2.0/24", []string{}},
+	}
+	for _, tt := range tests {
+		ranges, err := rdb.FindContaining(ctx, tt.ipRange)
+		if err != nil {
+			t.Errorf("rdb.FindContaining(%s) error = %v", tt.ipRange, err)
+			continue
+		}
+
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.FindContaining(%s) = %v, want %v", tt.ipRange, got, tt.want)
+		}
+	}
+}
+
+func TestClient_FindContainedBy(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "first"},
+		{"10.0.0.20 - 10.0.0.29", "second"},
+		{"10.0.0.40", "third"},
+		{"10.0.1.0/24", "fourth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange string
+		want    []string
+	}{
+		{"10.0.0.0/16", []string{"10.0.0.0 - 10.0.0.9 first", "10.0.0.20 - 10.0.0.29 second", "10.0.0.40 - 10.0.0.40 third", "10.0.1.0 - 10.0.1.255 fourth"}},
+		{"10.0.0.0/24", []string{"10.0.0.0 - 10.0.0.9 first", "10.0.0.20 - 10.0.0.29 second", "10.0.0.40 - 10.0.0.40 third"}},
+		{"10.0.0.5 - 10.0.0.40", []string{"10.0.0.20 - 10.0.0.29 second", "10.0.0.40 - 10.0.0.40 third"}},
+		{"10.0.0.20 - 10.0.1.128", []string{"10.0.0.20 - 10.0.0.29 second", "10.0.0.40 - 10.0.0.40 third"}},
+		{"10.0.0.21 - 10.0.0.28", []string{}},
+		{"10.0.0.10 - 10.0.0.19", []string{}},
+	}
+	for _, tt := range tests {
+		ranges, err := rdb.FindContainedBy(ctx, tt.ipRange)
+		if err != nil {
+			t.Errorf("rdb.FindContainedBy(%s) error = %v", tt.ipRange, err)
+			continue
+		}
+
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.FindContainedBy(%s) = %v, want %v", tt.ipRange, got, tt.want)
+		}
+	}
+}
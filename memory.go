@@ -0,0 +1,333 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/xgfone/go-netaddr"
+)
+
+// MemoryClient stores the IP ranges in a sorted slice of boundaries instead of a redis database.
+// It uses the same boundary algorithm as the Client, which is why both of them store the same ranges
+// after the same sequence of operations. The ranges are lost when the MemoryClient is garbage collected.
+// It is meant for tests and small embedded applications that do not want to depend on a redis server.
+type MemoryClient struct {
+	mu   sync.RWMutex
+	bnds memoryBoundaries
+}
+
+var (
+	_ RangeStore    = (*MemoryClient)(nil)
+	_ boundaryStore = (*memoryBoundaries)(nil)
+)
+
+// NewMemoryClient creates a new empty MemoryClient.
+func NewMemoryClient() *MemoryClient {
+	return &MemoryClient{
+		bnds: memoryBoundaries{negInfBoundary, posInfBoundary},
+	}
+}
+
+// Close does nothing, as there is no connection to close.
+func (m *MemoryClient) Close() error {
+	return nil
+}
+
+// Flush removes all ranges. In contrast to the Client, the global boundaries are kept,
+// as there is no database that could be shared with other data.
+func (m *MemoryClient) Flush(ctx context.Context) error {
+	return m.Reset(ctx)
+}
+
+// Reset removes all ranges.
+func (m *MemoryClient) Reset(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bnds = memoryBoundaries{negInfBoundary, posInfBoundary}
+	return nil
+}
+
+// Insert inserts a new IP range or IP with an associated reason string like Client.Insert.
+func (m *MemoryClient) Insert(ctx context.Context, ipRange, reason string) error {
+	low, high, err := parseRange(ipRange, reason)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.insert(low, high)
+}
+
+// insert is the unlocked implementation of Insert for already parsed boundaries.
+func (m *MemoryClient) insert(low, high boundary) error {
+	below, inside, above, err := storeVicinity(&m.bnds, low, high)
+	if err != nil {
+		return err
+	}
+
+	insertWithin(&m.bnds, low, high, below, inside, above)
+	return nil
+}
+
+// Remove removes an IP range like Client.Remove.
+func (m *MemoryClient) Remove(ctx context.Context, ipRange string) error {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.remove(low, high)
+}
+
+// remove is the unlocked implementation of Remove for already parsed boundaries.
+func (m *MemoryClient) remove(low, high boundary) error {
+	below, inside, above, err := storeVicinity(&m.bnds, low, high)
+	if err != nil {
+		return err
+	}
+
+	removeWithin(&m.bnds, low, high, below, inside, above)
+	return nil
+}
+
+// Find returns the reason of the range that contains the passed IP or ErrIPNotFound.
+func (m *MemoryClient) Find(ctx context.Context, ip string) (reason string, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.find(ip)
+}
+
+// find is the unlocked implementation of Find.
+func (m *MemoryClient) find(ip string) (reason string, err error) {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return "", fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", true, true)
+
+	below, inside, above, err := storeVicinity(&m.bnds, bnd, bnd)
+	if err != nil {
+		return "", err
+	}
+	return reasonWithin(below, inside, above)
+}
+
+// FindMany searches for all of the passed IPs like Client.FindMany.
+func (m *MemoryClient) FindMany(ctx context.Context, ips []string) (map[string]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	reasons := make(map[string]string, len(ips))
+	var notFound []string
+	for _, ip := range ips {
+		reason, err := m.find(ip)
+		if errors.Is(err, ErrIPNotFound) {
+			reasons[ip] = ""
+			notFound = append(notFound, ip)
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", ip, err)
+		}
+		reasons[ip] = reason
+	}
+
+	if len(notFound) > 0 {
+		return reasons, &NotFoundError{IPs: notFound}
+	}
+	return reasons, nil
+}
+
+// UpdateReasonOf updates the reason of the range that contains the passed ip.
+// fn is called once with the current reason of that range.
+func (m *MemoryClient) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) error {
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", true, true)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	below, inside, above, err := storeVicinity(&m.bnds, bnd, bnd)
+	if err != nil {
+		return err
+	}
+	r, err := containingWithin(below, inside, above)
+	if err != nil {
+		return err
+	}
+
+	bnds := []boundary{
+		newBoundary(r.Low, "", false, false),
+		newBoundary(r.High, "", false, false),
+	}
+	err = m.bnds.GetBoundaryAttrs(bnds)
+	if err != nil {
+		return err
+	}
+
+	reason := fn(r.Reason)
+	for _, bnd := range bnds {
+		bnd.Reason = reason
+		m.bnds.SetBoundaryAttrs(bnd)
+	}
+	return nil
+}
+
+// InsertBatch inserts all of the passed entries in their order.
+// Nothing is inserted if any of the entries is invalid.
+func (m *MemoryClient) InsertBatch(ctx context.Context, entries []RangeEntry) error {
+	parsed := make([]parsedRange, 0, len(entries))
+	for idx, entry := range entries {
+		low, high, err := parseRange(entry.Range, entry.Reason)
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", idx, err)
+		}
+		parsed = append(parsed, parsedRange{low, high})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range parsed {
+		err := m.insert(r.low, r.high)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveBatch removes all of the passed ranges in their order.
+// Nothing is removed if any of the ranges is invalid.
+func (m *MemoryClient) RemoveBatch(ctx context.Context, ranges []string) error {
+	parsed := make([]parsedRange, 0, len(ranges))
+	for idx, ipRange := range ranges {
+		low, high, err := parseRange(ipRange, "")
+		if err != nil {
+			return fmt.Errorf("range %d: %w", idx, err)
+		}
+		parsed = append(parsed, parsedRange{low, high})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range parsed {
+		err := m.remove(r.low, r.high)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All returns all of the stored ranges in ascending order.
+func (m *MemoryClient) All(ctx context.Context) ([]RangeInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ranges, _ := pairRanges(nil, m.bnds)
+	return ranges, nil
+}
+
+// memoryBoundaries is a slice of boundaries that is sorted by their scores and contains their attributes.
+// It always contains the ±inf boundaries and implements the boundaryStore.
+type memoryBoundaries []boundary
+
+// search returns the index of the first boundary whose score is not below the score of b
+// and whether that boundary has the same score.
+func (bnds memoryBoundaries) search(b boundary) (int, bool) {
+	idx := sort.Search(len(bnds), func(i int) bool {
+		return bnds[i].Float64 >= b.Float64
+	})
+	return idx, idx < len(bnds) && bnds[idx].Float64 == b.Float64
+}
+
+func (bnds *memoryBoundaries) ZAddBoundary(b boundary) {
+	idx, ok := bnds.search(b)
+	if ok {
+		return
+	}
+
+	*bnds = append(*bnds, boundary{})
+	copy((*bnds)[idx+1:], (*bnds)[idx:])
+	(*bnds)[idx] = b
+}
+
+func (bnds *memoryBoundaries) ZRemBoundary(b boundary) {
+	idx, ok := bnds.search(b)
+	if !ok {
+		return
+	}
+	*bnds = append((*bnds)[:idx], (*bnds)[idx+1:]...)
+}
+
+// GetBoundaryAttrs resets the attributes of boundaries that are not stored and marks them as Expired,
+// like redis returns no attributes for missing hashes.
+func (bnds *memoryBoundaries) GetBoundaryAttrs(result []boundary) error {
+	for i := range result {
+		idx, ok := bnds.search(result[i])
+		if !ok {
+			result[i].LowerBound = false
+			result[i].UpperBound = false
+			result[i].Reason = ""
+			result[i].ExpiresAt = 0
+			result[i].Expired = true
+			continue
+		}
+
+		stored := (*bnds)[idx]
+		result[i].LowerBound = stored.LowerBound
+		result[i].UpperBound = stored.UpperBound
+		result[i].Reason = stored.Reason
+		result[i].ExpiresAt = stored.ExpiresAt
+		result[i].Expired = false
+	}
+	return nil
+}
+
+// SetBoundaryAttrs sets the attributes of a stored boundary. Attributes of boundaries that are not stored
+// are dropped, as the attributes are kept within the sorted slice.
+func (bnds *memoryBoundaries) SetBoundaryAttrs(b boundary) {
+	idx, ok := bnds.search(b)
+	if !ok {
+		return
+	}
+
+	(*bnds)[idx].LowerBound = b.LowerBound
+	(*bnds)[idx].UpperBound = b.UpperBound
+	(*bnds)[idx].Reason = b.Reason
+	(*bnds)[idx].ExpiresAt = b.ExpiresAt
+}
+
+func (bnds *memoryBoundaries) ZRangeByScore(min, max float64, reverse bool, count int64) ([]boundary, error) {
+	first, _ := bnds.search(boundary{Float64: min})
+	last := sort.Search(len(*bnds), func(i int) bool {
+		return (*bnds)[i].Float64 > max
+	})
+
+	result := make([]boundary, 0, last-first)
+	for i := first; i < last; i++ {
+		idx := i
+		if reverse {
+			idx = last - 1 - (i - first)
+		}
+		if count > 0 && int64(len(result)) == count {
+			break
+		}
+		result = append(result, (*bnds)[idx])
+	}
+	return result, nil
+}
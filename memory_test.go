@@ -0,0 +1,147 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMemoryClient_SameAsClient(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb := initRDB(1)
+	defer rdb.Close()
+	defer rdb.Reset(ctx)
+
+	mem := NewMemoryClient()
+	defer mem.Close()
+
+	type operation struct {
+		insert bool
+		rangeReason
+	}
+
+	ops := []operation{
+		{true, rangeReason{"10.0.0.0/16", "first"}},
+		{true, rangeReason{"10.0.1.0 - 10.0.1.255", "second"}},
+		{true, rangeReason{"10.0.0.128 - 10.0.2.10", "first"}},
+		{true, rangeReason{"10.0.5.5", "single"}},
+		{false, rangeReason{"10.0.4.0/24", ""}},
+		{true, rangeReason{"10.0.255.0 - 10.1.0.10", "third"}},
+		{false, rangeReason{"10.0.3.0...10.0.3.100", ""}},
+		{true, rangeReason{"192.168.0.0/24", "fourth"}},
+		{false, rangeReason{"192.168.0.10", ""}},
+	}
+
+	for _, op := range ops {
+		for _, store := range []RangeStore{rdb, mem} {
+			var err error
+			if op.insert {
+				err = store.Insert(ctx, op.Range, op.Reason)
+			} else {
+				err = store.Remove(ctx, op.Range)
+			}
+			if err != nil {
+				t.Fatalf("%T: %v error = %v", store, op, err)
+			}
+		}
+
+		want, err := rdb.All(ctx)
+		if err != nil {
+			t.Fatalf("rdb.All() error = %v", err)
+		}
+		got, err := mem.All(ctx)
+		if err != nil {
+			t.Fatalf("mem.All() error = %v", err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("after %v: mem.All() = %v, want %v", op, got, want)
+		}
+	}
+
+	ips := []string{"10.0.0.1", "10.0.1.1", "10.0.3.50", "10.0.5.5", "10.1.0.10", "192.168.0.10", "192.168.0.11"}
+	want, wantErr := rdb.FindMany(ctx, ips)
+	got, gotErr := mem.FindMany(ctx, ips)
+	if fmt.Sprint(got) != fmt.Sprint(want) || fmt.Sprint(gotErr) != fmt.Sprint(wantErr) {
+		t.Errorf("mem.FindMany() = %v, %v, want %v, %v", got, gotErr, want, wantErr)
+	}
+}
+
+func TestMemoryClient_UpdateReasonOf(t *testing.T) {
+	ctx := context.TODO()
+
+	mem := NewMemoryClient()
+	if err := mem.InsertBatch(ctx, []RangeEntry{
+		{"10.0.0.0/24", "old"},
+		{"10.0.1.1", "single"},
+	}); err != nil {
+		t.Fatalf("mem.InsertBatch() error = %v", err)
+	}
+
+	suffix := func(oldReason string) string { return oldReason + "-new" }
+	for _, ip := range []string{"10.0.0.200", "10.0.1.1"} {
+		if err := mem.UpdateReasonOf(ctx, ip, suffix); err != nil {
+			t.Fatalf("mem.UpdateReasonOf(%s) error = %v", ip, err)
+		}
+	}
+	if err := mem.UpdateReasonOf(ctx, "10.0.1.2", suffix); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("mem.UpdateReasonOf() error = %v, want %v", err, ErrIPNotFound)
+	}
+
+	for ip, want := range map[string]string{"10.0.0.0": "old-new", "10.0.0.255": "old-new", "10.0.1.1": "single-new"} {
+		got, err := mem.Find(ctx, ip)
+		if err != nil || got != want {
+			t.Errorf("mem.Find(%s) = %q, %v, want %q", ip, got, err, want)
+		}
+	}
+
+	if err := mem.RemoveBatch(ctx, []string{"10.0.0.0/24", "10.0.1.1"}); err != nil {
+		t.Fatalf("mem.RemoveBatch() error = %v", err)
+	}
+	ranges, _ := mem.All(ctx)
+	if len(ranges) != 0 {
+		t.Errorf("mem.All() = %v, want no ranges", ranges)
+	}
+}
+
+func TestUpdateReasonOf_UpperBound(t *testing.T) {
+	ctx := context.TODO()
+
+	for _, store := range rangeStores {
+		t.Run(store.name, func(t *testing.T) {
+			rdb := store.new()
+			defer rdb.Close()
+
+			if err := rdb.InsertBatch(ctx, []RangeEntry{
+				{"10.0.0.0 - 10.0.0.10", "first"},
+				{"10.0.0.20 - 10.0.0.30", "second"},
+			}); err != nil {
+				t.Fatalf("InsertBatch() error = %v", err)
+			}
+
+			// hitting the upper boundary must update the range with the reason of that range
+			calls := 0
+			err := rdb.UpdateReasonOf(ctx, "10.0.0.10", func(oldReason string) string {
+				calls++
+				return oldReason + "-new"
+			})
+			if err != nil {
+				t.Fatalf("UpdateReasonOf() error = %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("UpdateReasonOf() called fn %d times, want 1", calls)
+			}
+			if !consistent(rdb, t, "10.0.0.0 - 10.0.0.10", 1) {
+				t.Fatalf("database inconsistent after UpdateReasonOf()")
+			}
+
+			for ip, want := range map[string]string{"10.0.0.0": "first-new", "10.0.0.10": "first-new", "10.0.0.20": "second"} {
+				got, err := rdb.Find(ctx, ip)
+				if err != nil || got != want {
+					t.Errorf("Find(%s) = %q, %v, want %q", ip, got, err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package goripr
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// InsertNetwork inserts the passed network with an associated reason string.
+// In contrast to Insert the first and last IP of the network are computed directly
+// without any string formatting and parsing.
+func (c *Client) InsertNetwork(ctx context.Context, network *net.IPNet, reason string) error {
+	if network == nil {
+		return ErrInvalidRange
+	}
+
+	ip := network.IP.To4()
+	if ip == nil {
+		return ErrIPv6NotSupported
+	}
+
+	// IPv4 networks may have a 16 byte mask, e.g. the ones of net.IPNet values that are built by hand
+	mask := network.Mask
+	if len(mask) == net.IPv6len {
+		mask = mask[len(mask)-net.IPv4len:]
+	}
+
+	ones, bits := mask.Size()
+	if bits != 8*net.IPv4len {
+		return fmt.Errorf("%w : invalid network mask %s", ErrInvalidRange, network.Mask)
+	}
+
+	first := binary.BigEndian.Uint32(ip) & binary.BigEndian.Uint32(mask)
+	last := first | ^uint32(0)>>ones
+
+	reason = c.normalizeReason(reason)
+	low := newBoundary(ipFromInt64(int64(first)), reason, true, false)
+	high := newBoundary(ipFromInt64(int64(last)), reason, false, true)
+
+	err := c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	return c.insert(ctx, low, high)
+}
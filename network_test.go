@@ -0,0 +1,57 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestClient_InsertNetwork(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	for _, cidr := range []string{"10.0.0.17/24", "10.0.2.1/32"} {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatalf("net.ParseCIDR() error = %v", err)
+		}
+		if err := rdb.InsertNetwork(ctx, network, cidr); err != nil {
+			t.Fatalf("rdb.InsertNetwork() error = %v", err)
+		}
+	}
+
+	// IPv4 network with a 16 byte mask
+	mapped := &net.IPNet{IP: net.ParseIP("10.0.4.0"), Mask: net.CIDRMask(120, 8*net.IPv6len)}
+	if err := rdb.InsertNetwork(ctx, mapped, "mapped"); err != nil {
+		t.Fatalf("rdb.InsertNetwork() error = %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr error
+	}{
+		{"9.255.255.255", "", ErrIPNotFound},
+		{"10.0.0.0", "10.0.0.17/24", nil},
+		{"10.0.0.255", "10.0.0.17/24", nil},
+		{"10.0.1.0", "", ErrIPNotFound},
+		{"10.0.2.1", "10.0.2.1/32", nil},
+		{"10.0.2.2", "", ErrIPNotFound},
+		{"10.0.4.0", "mapped", nil},
+		{"10.0.4.255", "mapped", nil},
+		{"10.0.5.0", "", ErrIPNotFound},
+	}
+	for _, tt := range tests {
+		got, err := rdb.Find(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q, %v", tt.ip, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	_, ipv6, _ := net.ParseCIDR("2001:db8::/64")
+	if err := rdb.InsertNetwork(ctx, ipv6, "ipv6"); !errors.Is(err, ErrIPv6NotSupported) {
+		t.Errorf("rdb.InsertNetwork() error = %v, want %v", err, ErrIPv6NotSupported)
+	}
+}
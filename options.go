@@ -0,0 +1,96 @@
+package goripr
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Option configures optional behavior of a Client.
+type Option func(*Client)
+
+// WithCustomBoundaries replaces the -inf and +inf scores of the global boundaries with low and high.
+// This allows to manage a partition of the IP space, e.g. only a single IP prefix,
+// where the global boundaries are placed directly around the partition.
+// Only IPs that lie strictly between low and high can be inserted or found.
+// All clients use the same key names and the same members for the global boundaries, which is why
+// every partition needs its own database, e.g. a different Options.DB or redis server.
+// Clients with different boundaries must not share a database.
+func WithCustomBoundaries(low, high float64) Option {
+	return func(c *Client) {
+		c.minScore = low
+		c.maxScore = high
+	}
+}
+
+// WithDebugLogging logs every command that is sent to the database with its arguments, duration and error
+// on the debug level of logger. Passwords of AUTH and HELLO commands are redacted.
+func WithDebugLogging(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithReasonValidator validates every reason with fn before it is inserted or updated.
+// The error of fn is returned as is and nothing is written in that case.
+func WithReasonValidator(fn func(reason string) error) Option {
+	return func(c *Client) {
+		c.reasonValidator = fn
+	}
+}
+
+// WithSkipInfBoundaries controls whether All excludes the -inf and +inf global boundaries, which it does by default.
+// If skip is false, All returns the -inf boundary as its first and the +inf boundary as its last range.
+// Both ranges have nil IPs and the reason that is stored in the attributes of the boundary,
+// which helps to debug the state of the global boundaries.
+func WithSkipInfBoundaries(skip bool) Option {
+	return func(c *Client) {
+		c.skipInfBoundaries = skip
+	}
+}
+
+// WithInsertionOrder records the first IP of every inserted range in the InsertionOrderKey list,
+// which allows to list the ranges in the order of their insertion with ListByInsertionOrder.
+// The list is never trimmed, which is what WithInsertionOrderLimit is for. Removed ranges are skipped when the list is read.
+func WithInsertionOrder() Option {
+	return func(c *Client) {
+		c.insertionOrder = true
+	}
+}
+
+// WithInsertionOrderLimit records the insertions like WithInsertionOrder, but trims the InsertionOrderKey list
+// to the last limit insertions within the transaction of every insertion, which bounds the size of the list.
+// The offsets of ListByInsertionOrder start at the oldest insertion that is still recorded.
+// A limit below 1 does not trim the list.
+func WithInsertionOrderLimit(limit int64) Option {
+	return func(c *Client) {
+		c.insertionOrder = true
+		c.insertionOrderLimit = limit
+	}
+}
+
+// WithLowerCaseReasons converts every reason to lower case before it is stored by any of the methods of the Client,
+// which prevents reasons like "Malware" and "malware" from being treated as different reasons.
+// The reasons that FindByReason, DeleteByReason and RenameReason look for are converted as well.
+// The original casing of the reasons is not preserved. The reason validator sees the lower case reason.
+// Reasons that were stored before the option was enabled are not converted.
+func WithLowerCaseReasons() Option {
+	return func(c *Client) {
+		c.lowerCaseReasons = true
+	}
+}
+
+// normalizeReason converts the reason to lower case if the Client was created WithLowerCaseReasons.
+func (c *Client) normalizeReason(reason string) string {
+	if !c.lowerCaseReasons {
+		return reason
+	}
+	return strings.ToLower(reason)
+}
+
+// validateReason returns the error of the reason validator if one is set.
+func (c *Client) validateReason(reason string) error {
+	if c.reasonValidator == nil {
+		return nil
+	}
+	return c.reasonValidator(reason)
+}
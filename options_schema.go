@@ -0,0 +1,272 @@
+package goripr
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// optionsSchema is the JSON Schema of the Options that can be represented as JSON.
+//
+//go:embed options.schema.json
+var optionsSchema []byte
+
+// schemaProperty is the subset of the JSON Schema keywords that options.schema.json uses for its properties.
+type schemaProperty struct {
+	Description string        `json:"description"`
+	Type        string        `json:"type"`
+	Enum        []interface{} `json:"enum"`
+	Minimum     *float64      `json:"minimum"`
+	Maximum     *float64      `json:"maximum"`
+	MinLength   int           `json:"minLength"`
+	MinItems    int           `json:"minItems"`
+	Format      string        `json:"format"`
+	// Items is the schema of the elements of an array.
+	Items *schemaProperty `json:"items"`
+}
+
+// schemaObject is the subset of the JSON Schema keywords that options.schema.json uses for the Options object.
+type schemaObject struct {
+	ID                   string                    `json:"$id"`
+	Title                string                    `json:"title"`
+	Description          string                    `json:"description"`
+	Type                 string                    `json:"type"`
+	Required             []string                  `json:"required"`
+	AdditionalProperties *bool                     `json:"additionalProperties"`
+	Properties           map[string]schemaProperty `json:"properties"`
+	// AnyOf requires the object to be valid against at least one of the schemas.
+	AnyOf []schemaObject `json:"anyOf"`
+	// Not requires the object to be invalid against the schema.
+	Not *schemaObject `json:"not"`
+	// DependentRequired maps a property to the properties that are required if it is present.
+	DependentRequired map[string][]string `json:"dependentRequired"`
+}
+
+// ValidateOptionsJSON validates the JSON object against options.schema.json.
+// The keys of the object are the names of the Options fields and durations are strings in the
+// format of time.ParseDuration. ErrInvalidOptions is returned for the first violation.
+//
+// This is not a general JSON Schema validator. It supports the keywords type, enum, minimum,
+// maximum, minLength, minItems, items and format "duration" for properties as well as
+// required, properties, additionalProperties, anyOf, not and dependentRequired for the object.
+// Any other keyword except for the annotations $id, title and description is rejected as an invalid schema,
+// which is why changes of the schema cannot weaken the validation unnoticed.
+func ValidateOptionsJSON(jsonData []byte) error {
+	schema, err := parseSchema(optionsSchema)
+	if err != nil {
+		return fmt.Errorf("%w : invalid schema: %v", ErrInvalidOptions, err)
+	}
+
+	var object map[string]interface{}
+	err = json.Unmarshal(jsonData, &object)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidOptions, err)
+	}
+	if object == nil {
+		return fmt.Errorf("%w : expected a JSON object", ErrInvalidOptions)
+	}
+
+	err = schema.validate(object)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidOptions, err)
+	}
+	return nil
+}
+
+// parseSchema decodes the schema and returns an error if it uses any keyword or value that the
+// validator does not implement.
+func parseSchema(data []byte) (schemaObject, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var schema schemaObject
+	err := dec.Decode(&schema)
+	if err != nil {
+		return schemaObject{}, err
+	}
+
+	err = schema.check()
+	if err != nil {
+		return schemaObject{}, err
+	}
+	return schema, nil
+}
+
+// check returns an error if the object schema or any of its sub-schemas uses an unsupported value.
+func (o schemaObject) check() error {
+	if o.Type != "" && o.Type != "object" {
+		return fmt.Errorf("unsupported object type %q", o.Type)
+	}
+
+	for key, property := range o.Properties {
+		err := property.check()
+		if err != nil {
+			return fmt.Errorf("property %s: %v", key, err)
+		}
+	}
+
+	for _, schema := range o.AnyOf {
+		err := schema.check()
+		if err != nil {
+			return fmt.Errorf("anyOf: %v", err)
+		}
+	}
+
+	if o.Not != nil {
+		err := o.Not.check()
+		if err != nil {
+			return fmt.Errorf("not: %v", err)
+		}
+	}
+	return nil
+}
+
+// check returns an error if the property uses an unsupported type or a keyword that does not apply to its type.
+func (p schemaProperty) check() error {
+	switch p.Type {
+	case "string", "integer", "boolean", "array":
+	default:
+		return fmt.Errorf("unsupported type %q", p.Type)
+	}
+
+	switch {
+	case p.Format != "" && (p.Type != "string" || p.Format != "duration"):
+		return fmt.Errorf("unsupported format %q of type %s", p.Format, p.Type)
+	case p.MinLength != 0 && p.Type != "string":
+		return fmt.Errorf("minLength does not apply to type %s", p.Type)
+	case (p.Minimum != nil || p.Maximum != nil) && p.Type != "integer":
+		return fmt.Errorf("minimum and maximum do not apply to type %s", p.Type)
+	case (p.MinItems != 0 || p.Items != nil) && p.Type != "array":
+		return fmt.Errorf("minItems and items do not apply to type %s", p.Type)
+	}
+
+	if p.Items != nil {
+		err := p.Items.check()
+		if err != nil {
+			return fmt.Errorf("items: %v", err)
+		}
+	}
+	return nil
+}
+
+// validate returns an error if object violates any of the keywords of the schema.
+func (o schemaObject) validate(object map[string]interface{}) error {
+	for _, key := range o.Required {
+		if _, ok := object[key]; !ok {
+			return fmt.Errorf("missing required property %s", key)
+		}
+	}
+
+	// sorted in order to always report the same violation first
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, dependency := range o.DependentRequired[key] {
+			if _, ok := object[dependency]; !ok {
+				return fmt.Errorf("property %s requires property %s", key, dependency)
+			}
+		}
+
+		property, ok := o.Properties[key]
+		if !ok {
+			if o.AdditionalProperties != nil && !*o.AdditionalProperties {
+				return fmt.Errorf("unknown property %s", key)
+			}
+			continue
+		}
+
+		err := property.validate(object[key])
+		if err != nil {
+			return fmt.Errorf("property %s: %v", key, err)
+		}
+	}
+
+	if len(o.AnyOf) > 0 {
+		var errs []error
+		for _, schema := range o.AnyOf {
+			err := schema.validate(object)
+			if err == nil {
+				errs = nil
+				break
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("matches none of the alternatives: %v", errors.Join(errs...))
+		}
+	}
+
+	if o.Not != nil && o.Not.validate(object) == nil {
+		return errors.New("matches a forbidden combination of properties")
+	}
+	return nil
+}
+
+// validate returns an error if value violates any of the keywords of the property.
+func (p schemaProperty) validate(value interface{}) error {
+	switch p.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if len(s) < p.MinLength {
+			return fmt.Errorf("expected at least %d characters", p.MinLength)
+		}
+		if p.Format == "duration" {
+			if _, err := time.ParseDuration(s); err != nil {
+				return err
+			}
+		}
+	case "integer":
+		f, ok := value.(float64)
+		if !ok || f != math.Trunc(f) {
+			return fmt.Errorf("expected an integer, got %v", value)
+		}
+		if p.Minimum != nil && f < *p.Minimum {
+			return fmt.Errorf("%v is less than the minimum %v", f, *p.Minimum)
+		}
+		if p.Maximum != nil && f > *p.Maximum {
+			return fmt.Errorf("%v is greater than the maximum %v", f, *p.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+	case "array":
+		elements, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("expected an array, got %T", value)
+		}
+		if len(elements) < p.MinItems {
+			return fmt.Errorf("expected at least %d elements", p.MinItems)
+		}
+		if p.Items == nil {
+			break
+		}
+		for idx, element := range elements {
+			if err := p.Items.validate(element); err != nil {
+				return fmt.Errorf("element %d: %v", idx, err)
+			}
+		}
+	}
+
+	if len(p.Enum) == 0 {
+		return nil
+	}
+	for _, allowed := range p.Enum {
+		if allowed == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not one of %v", value, p.Enum)
+}
@@ -0,0 +1,271 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithCustomBoundaries(t *testing.T) {
+	ctx := context.TODO()
+
+	low := newBoundary("10.0.0.0", "", false, false)
+	high := newBoundary("10.0.0.255", "", false, false)
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithCustomBoundaries(low.Float64, high.Float64))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "partition"); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("rdb.Insert() error = %v, want %v", err, ErrInvalidRange)
+	}
+	if err := rdb.UpdateReasonOfRange(ctx, "10.0.0.0/24", strings.ToUpper); !errors.Is(err, ErrInvalidRange) {
+		t.Fatalf("rdb.UpdateReasonOfRange() error = %v, want %v", err, ErrInvalidRange)
+	}
+
+	inserts := []rangeReason{
+		{"10.0.0.1 - 10.0.0.10", "first"},
+		{"10.0.0.254", "second"},
+		{"10.0.0.5 - 10.0.0.100", "third"},
+	}
+	for idx, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+		if !consistent(rdb, t, ir.Range, idx) {
+			t.Fatalf("database INCONSISTENT after inserting range: %s", ir.Range)
+		}
+	}
+
+	tests := []struct {
+		ip      string
+		reason  string
+		wantErr error
+	}{
+		{"10.0.0.0", "", ErrIPNotFound},
+		{"10.0.0.1", "first", nil},
+		{"10.0.0.50", "third", nil},
+		{"10.0.0.254", "second", nil},
+		{"10.0.0.255", "", ErrIPNotFound},
+		{"11.0.0.0", "", ErrIPNotFound},
+	}
+	for _, tt := range tests {
+		reason, err := rdb.Find(ctx, tt.ip)
+		if reason != tt.reason || !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q, %v", tt.ip, reason, err, tt.reason, tt.wantErr)
+		}
+	}
+
+	covered, err := rdb.CoveredInSubnet(ctx, "10.0.0.0/24")
+	if err != nil || len(covered) != 101 {
+		t.Fatalf("rdb.CoveredInSubnet() returned %d IPs, %v, want 101", len(covered), err)
+	}
+
+	got := 0
+	s := rdb.Scanner(ctx, 1)
+	for s.Next(ctx) {
+		got += len(s.RangeInfos())
+	}
+	if s.Err() != nil || got != 3 {
+		t.Fatalf("rdb.Scanner() returned %d ranges, %v, want 3", got, s.Err())
+	}
+
+	if err := rdb.Remove(ctx, "10.0.0.254"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+	if !consistent(rdb, t, "", 0) {
+		t.Fatalf("database INCONSISTENT after removal")
+	}
+}
+
+func TestWithReasonValidator(t *testing.T) {
+	ctx := context.TODO()
+
+	errNoSpaces := errors.New("reason must not contain spaces")
+	validator := func(reason string) error {
+		if strings.Contains(reason, " ") {
+			return errNoSpaces
+		}
+		return nil
+	}
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithReasonValidator(validator))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "in valid"); !errors.Is(err, errNoSpaces) {
+		t.Fatalf("rdb.Insert() error = %v, want %v", err, errNoSpaces)
+	}
+	if err := rdb.InsertBatch(ctx, []RangeEntry{{"10.0.0.0/24", "in valid"}}); !errors.Is(err, errNoSpaces) {
+		t.Fatalf("rdb.InsertBatch() error = %v, want %v", err, errNoSpaces)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "valid"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	err = rdb.UpdateReasonOf(ctx, "10.0.0.1", func(string) string { return "in valid" })
+	if !errors.Is(err, errNoSpaces) {
+		t.Fatalf("rdb.UpdateReasonOf() error = %v, want %v", err, errNoSpaces)
+	}
+
+	reason, err := rdb.Find(ctx, "10.0.0.1")
+	if err != nil || reason != "valid" {
+		t.Fatalf("rdb.Find() = %q, %v, want %q", reason, err, "valid")
+	}
+}
+
+func TestWithSkipInfBoundaries(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithSkipInfBoundaries(false))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "first"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	ranges, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	if len(ranges) != 3 || ranges[0].Reason != "-inf" || ranges[0].Low != nil ||
+		ranges[1].Reason != "first" || ranges[2].Reason != "+inf" || ranges[2].High != nil {
+		t.Errorf("rdb.All() = %v, want the -inf, first and +inf ranges", ranges)
+	}
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+}
+
+func TestWithLowerCaseReasons(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithLowerCaseReasons())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+	defer rdb.Reset(ctx)
+
+	network := &net.IPNet{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(24, 32)}
+	upper := func(string) string { return "Malware" }
+
+	tests := []struct {
+		name  string
+		write func() error
+		want  map[string]string
+	}{
+		{"Insert", func() error {
+			return rdb.Insert(ctx, "10.0.0.0/24", "Malware")
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"InsertBatch", func() error {
+			return rdb.InsertBatch(ctx, []RangeEntry{{"10.0.0.0/24", "Malware"}})
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"UpdateReasonOf", func() error {
+			if err := rdb.Insert(ctx, "10.0.0.0/24", "other"); err != nil {
+				return err
+			}
+			return rdb.UpdateReasonOf(ctx, "10.0.0.1", upper)
+		}, map[string]string{"10.0.0.0": "malware", "10.0.0.255": "malware"}},
+		{"InsertOrUpdate", func() error {
+			return rdb.InsertOrUpdate(ctx, "10.0.0.0/24", "Malware")
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"UpsertBatch", func() error {
+			_, _, err := rdb.UpsertBatch(ctx, []RangeEntry{{"10.0.0.0/24", "Malware"}})
+			return err
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"InsertIfNoOverlap", func() error {
+			return rdb.InsertIfNoOverlap(ctx, "10.0.0.0/24", "Malware")
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"ReplaceRange", func() error {
+			if err := rdb.Insert(ctx, "10.0.0.0/25", "other"); err != nil {
+				return err
+			}
+			return rdb.ReplaceRange(ctx, "10.0.0.0/25", "10.0.0.0/24", "Malware")
+		}, map[string]string{"10.0.0.1": "malware", "10.0.0.200": "malware"}},
+		{"SplitRange", func() error {
+			if err := rdb.Insert(ctx, "10.0.0.0/24", "other"); err != nil {
+				return err
+			}
+			return rdb.SplitRange(ctx, "10.0.0.127", "Malware", "Spam")
+		}, map[string]string{"10.0.0.1": "malware", "10.0.0.200": "spam"}},
+		{"UpdateReasonOfRange", func() error {
+			if err := rdb.Insert(ctx, "10.0.0.0/24", "other"); err != nil {
+				return err
+			}
+			return rdb.UpdateReasonOfRange(ctx, "10.0.0.0/24", upper)
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"RenameReason", func() error {
+			if err := rdb.Insert(ctx, "10.0.0.0/24", "other"); err != nil {
+				return err
+			}
+			_, err := rdb.RenameReason(ctx, "Other", "Malware")
+			return err
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"InsertNetwork", func() error {
+			return rdb.InsertNetwork(ctx, network, "Malware")
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"InsertWithTTL", func() error {
+			return rdb.InsertWithTTL(ctx, "10.0.0.0/24", "Malware", time.Hour)
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"ImportJSON", func() error {
+			data := `[{"low":"10.0.0.0","high":"10.0.0.255","reason":"Malware"}]`
+			return rdb.ImportJSON(ctx, strings.NewReader(data), false)
+		}, map[string]string{"10.0.0.1": "malware"}},
+		{"ImportCSV", func() error {
+			return rdb.ImportCSV(ctx, strings.NewReader("10.0.0.0,10.0.0.255,Malware\n"), false)
+		}, map[string]string{"10.0.0.1": "malware"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := rdb.Reset(ctx); err != nil {
+				t.Fatalf("rdb.Reset() error = %v", err)
+			}
+			if err := tt.write(); err != nil {
+				t.Fatalf("%s error = %v", tt.name, err)
+			}
+			for ip, want := range tt.want {
+				got, err := rdb.Find(ctx, ip)
+				if err != nil || got != want {
+					t.Errorf("rdb.Find(%s) = %q, %v, want %q", ip, got, err, want)
+				}
+			}
+		})
+	}
+
+	// reasons that are looked for are converted as well
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "Malware"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if got, err := rdb.FindByReason(ctx, "MALWARE"); err != nil || len(got) != 1 {
+		t.Errorf("rdb.FindByReason() = %v, %v, want a single range", got, err)
+	}
+	if removed, err := rdb.DeleteByReason(ctx, "MALWARE"); err != nil || removed != 1 {
+		t.Errorf("rdb.DeleteByReason() = %d, %v, want 1", removed, err)
+	}
+}
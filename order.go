@@ -0,0 +1,161 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xgfone/go-netaddr"
+)
+
+// ListByInsertionOrder returns up to count ranges in the order in which they were inserted, starting at the
+// offset-th insertion. It requires a client that was created WithInsertionOrder.
+// Every insertion is resolved to the range that currently contains its first IP, which is why ranges that were
+// merged or cut by later insertions are returned as they are stored now and may be returned multiple times.
+// Insertions whose first IP is not contained in any range anymore are skipped.
+func (c *Client) ListByInsertionOrder(ctx context.Context, offset, count int64) ([]RangeInfo, error) {
+	if offset < 0 || count < 1 {
+		return []RangeInfo{}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.insertedRanges(ctx, offset, offset+count-1)
+}
+
+// MostRecentInserts returns the ranges of the last n insertions, starting with the oldest of them.
+// It requires a client that was created WithInsertionOrder and resolves the insertions to the stored ranges
+// the same way ListByInsertionOrder does, which is why fewer than n ranges may be returned.
+func (c *Client) MostRecentInserts(ctx context.Context, n int64) ([]RangeInfo, error) {
+	if n < 1 {
+		return []RangeInfo{}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.insertedRanges(ctx, -n, -1)
+}
+
+// insertedRanges resolves the insertions from start to stop of the InsertionOrderKey list to the ranges
+// that contain their first IPs. start and stop follow the LRANGE index syntax.
+// The vicinities of all insertions are looked up within two pipelined round trips like FindBatch does.
+func (c *Client) insertedRanges(ctx context.Context, start, stop int64) ([]RangeInfo, error) {
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
+
+	ips, err := c.rdb.LRange(ctx, InsertionOrderKey, start, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	bnds := make([]boundary, len(ips))
+	belowCmds := make([]*redis.ZSliceCmd, len(ips))
+	aboveCmds := make([]*redis.ZSliceCmd, len(ips))
+
+	tx := c.rdb.TxPipeline()
+	for idx, ip := range ips {
+		ipaddr, err := netaddr.NewIPAddress(ip, 4)
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrDatabaseInconsistent, err)
+		}
+		bnds[idx] = newBoundary(ipaddr.IP(), "", true, true)
+
+		// the IP itself, if it is a boundary, and the nearest boundary below it
+		belowCmds[idx] = tx.ZRevRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
+			Min:   "-inf",
+			Max:   bnds[idx].Int64String(),
+			Count: 2,
+		})
+
+		// nearest boundary above the IP
+		aboveCmds[idx] = tx.ZRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
+			Min:   bnds[idx].Above().Int64String(),
+			Max:   "+inf",
+			Count: 1,
+		})
+	}
+
+	_, err = tx.Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	// boundaries that are shared between multiple insertions are only fetched once
+	nearest := make([][]int, len(ips))
+	unique := make([]boundary, 0, 3*len(ips))
+	indexOf := make(map[string]int, 3*len(ips))
+
+	add := func(z redis.Z) int {
+		bnd := c.boundaryOf(z)
+		if idx, ok := indexOf[bnd.ID]; ok {
+			return idx
+		}
+		indexOf[bnd.ID] = len(unique)
+		unique = append(unique, bnd)
+		return len(unique) - 1
+	}
+
+	for idx := range ips {
+		below, err := belowCmds[idx].Result()
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+		above, err := aboveCmds[idx].Result()
+		if err != nil {
+			return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+		if len(below) == 0 || len(above) == 0 {
+			return nil, ErrDatabaseInconsistent
+		}
+
+		for _, z := range below {
+			nearest[idx] = append(nearest[idx], add(z))
+		}
+		nearest[idx] = append(nearest[idx], add(above[0]))
+	}
+
+	err = c.fetchAttributes(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RangeInfo, 0, len(ips))
+	for idx := range ips {
+		var (
+			belowN = make([]boundary, 0, 2)
+			inside []boundary
+			aboveN = []boundary{unique[nearest[idx][len(nearest[idx])-1]]}
+		)
+		expired := aboveN[0].Expired
+		for _, n := range nearest[idx][:len(nearest[idx])-1] {
+			expired = expired || unique[n].Expired
+			if unique[n].EqualIP(bnds[idx]) {
+				inside = append(inside, unique[n])
+				continue
+			}
+			belowN = append(belowN, unique[n])
+		}
+
+		var (
+			r   RangeInfo
+			err error
+		)
+		if expired {
+			// expired boundaries are skipped by looking further
+			r, err = c.containingRange(ctx, bnds[idx])
+		} else {
+			r, err = containingWithin(belowN, inside, aboveN)
+		}
+		if errors.Is(err, ErrIPNotFound) || errors.Is(err, ErrRangeExpired) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,158 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestClient_ListByInsertionOrder(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithInsertionOrder())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	defer rdb.Reset(ctx)
+
+	inserts := []rangeReason{
+		{"10.0.2.0/24", "first"},
+		{"10.0.0.5", "second"},
+		{"10.0.1.0 - 10.0.1.9", "third"},
+		{"10.0.3.0", "removed"},
+		{"10.0.4.5 - 10.0.4.9", "cut"},
+		// the first IP of the previous insertion becomes the upper boundary of this range
+		{"10.0.4.0 - 10.0.4.5", "over"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+	if err := rdb.Remove(ctx, "10.0.3.0"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+
+	tests := []struct {
+		offset, count int64
+		want          []string
+	}{
+		{0, 10, []string{"first", "second", "third", "over", "over"}},
+		{1, 1, []string{"second"}},
+		{2, 2, []string{"third"}},
+		{3, 1, []string{}},
+		{4, 2, []string{"over", "over"}},
+		{6, 1, []string{}},
+		{0, 0, []string{}},
+	}
+	for _, tt := range tests {
+		ranges, err := rdb.ListByInsertionOrder(ctx, tt.offset, tt.count)
+		if err != nil {
+			t.Fatalf("rdb.ListByInsertionOrder() error = %v", err)
+		}
+
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.ListByInsertionOrder(%d, %d) = %v, want %v", tt.offset, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestClient_MostRecentInserts(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithInsertionOrder())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	defer rdb.Reset(ctx)
+
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.0/24", "second"},
+		{"10.0.2.0/24", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		n    int64
+		want []string
+	}{
+		{2, []string{"second", "third"}},
+		{1, []string{"third"}},
+		{10, []string{"first", "second", "third"}},
+		{0, []string{}},
+	}
+	for _, tt := range tests {
+		ranges, err := rdb.MostRecentInserts(ctx, tt.n)
+		if err != nil {
+			t.Fatalf("rdb.MostRecentInserts() error = %v", err)
+		}
+
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.MostRecentInserts(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestClient_InsertionOrderLimit(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 1}, WithInsertionOrderLimit(2))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	defer rdb.Reset(ctx)
+
+	for _, ir := range []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.0/24", "second"},
+		{"10.0.2.0/24", "third"},
+	} {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	if n, err := rdb.rdb.LLen(ctx, InsertionOrderKey).Result(); err != nil || n != 2 {
+		t.Fatalf("LLEN %s = %d, %v, want 2", InsertionOrderKey, n, err)
+	}
+
+	ranges, err := rdb.ListByInsertionOrder(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("rdb.ListByInsertionOrder() error = %v", err)
+	}
+	got := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		got = append(got, r.Reason)
+	}
+	if want := []string{"second", "third"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rdb.ListByInsertionOrder(0, 10) = %v, want %v", got, want)
+	}
+}
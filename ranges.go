@@ -0,0 +1,257 @@
+package goripr
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RangeInfo describes a single IP range that is stored in the database.
+type RangeInfo struct {
+	Low    net.IP
+	High   net.IP
+	Reason string
+}
+
+// RangeEntry is an IP range in any of the formats that Insert accepts together with its reason.
+type RangeEntry struct {
+	Range  string `json:"range"`
+	Reason string `json:"reason"`
+}
+
+// boundariesFrom retrieves up to count boundaries with a score within the interval [min, +inf]
+// including their attributes. min follows the redis score syntax, e.g. "-inf" or "(123" for exclusive minimums.
+// A count < 1 retrieves all of the boundaries.
+func (c *Client) boundariesFrom(ctx context.Context, min string, count int64) ([]boundary, error) {
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
+
+	zrange := &redis.ZRangeBy{
+		Min: min,
+		Max: "+inf",
+	}
+	if count > 0 {
+		zrange.Count = count
+	}
+
+	results, err := c.rdb.ZRangeByScoreWithScores(ctx, IPRangesKey, zrange).Result()
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	bnds := make([]boundary, 0, len(results))
+	for _, result := range results {
+		bnds = append(bnds, c.boundaryOf(result))
+	}
+
+	err = c.fetchAttributes(ctx, bnds)
+	if err != nil {
+		return nil, err
+	}
+	return bnds, nil
+}
+
+// fetchAttributes retrieves the lower, upper and reason attributes of all passed boundaries
+// within a single transaction.
+func (c *Client) fetchAttributes(ctx context.Context, bnds []boundary) error {
+	return c.newRedisStore(ctx, nil).GetBoundaryAttrs(bnds)
+}
+
+// pairRanges combines the sorted boundaries into ranges.
+// pending is a lower boundary of a previous call that has not been closed by an upper boundary, yet.
+// The returned lower boundary is such a pending boundary that must be passed to the next call.
+// Upper boundaries without a preceding lower boundary as well as the ±inf boundaries are skipped.
+func pairRanges(pending *boundary, bnds []boundary) ([]RangeInfo, *boundary) {
+	result := make([]RangeInfo, 0, len(bnds)/2+1)
+
+	for idx := range bnds {
+		bnd := bnds[idx]
+		if bnd.IsInfBound() {
+			continue
+		}
+
+		if bnd.LowerBound && bnd.UpperBound {
+			result = append(result, RangeInfo{
+				Low:    bnd.IP,
+				High:   bnd.IP,
+				Reason: bnd.Reason,
+			})
+			pending = nil
+		} else if bnd.LowerBound {
+			pending = &bnds[idx]
+		} else if bnd.UpperBound && pending != nil {
+			result = append(result, RangeInfo{
+				Low:    pending.IP,
+				High:   bnd.IP,
+				Reason: bnd.Reason,
+			})
+			pending = nil
+		}
+	}
+	return result, pending
+}
+
+// All returns all of the stored ranges in ascending order.
+// The lower and upper boundaries of every range are combined into a single RangeInfo.
+// The global boundaries are only returned if the Client was created WithSkipInfBoundaries(false).
+// GlobalBoundaries returns their scores and types as well.
+func (c *Client) All(ctx context.Context) ([]RangeInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.skipInfBoundaries {
+		return c.listAll(ctx)
+	}
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	ranges, _ := pairRanges(nil, bnds)
+
+	result := make([]RangeInfo, 0, len(ranges)+2)
+	for _, bnd := range bnds {
+		if bnd.ID == negInfBoundary.ID {
+			result = append(result, RangeInfo{Reason: bnd.Reason})
+		}
+	}
+	result = append(result, ranges...)
+	for _, bnd := range bnds {
+		if bnd.ID == posInfBoundary.ID {
+			result = append(result, RangeInfo{Reason: bnd.Reason})
+		}
+	}
+	return result, nil
+}
+
+// ListAllPage returns up to limit stored ranges in ascending order that start after the range of the passed cursor.
+// The empty cursor starts at the first range. The returned cursor must be passed to the next call and is empty
+// if there are no more ranges. It contains the first IP of the last returned range, which is why changes between
+// two calls neither cause ranges to be returned twice nor to be skipped, unless they are inserted before the cursor.
+// A limit < 1 returns all of the ranges.
+func (c *Client) ListAllPage(ctx context.Context, cursor string, limit int) (results []RangeInfo, nextCursor string, err error) {
+	min := "-inf"
+	if cursor != "" {
+		last, err := strconv.ParseUint(cursor, 10, 32)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w : %v", ErrInvalidCursor, err)
+		}
+		min = "(" + strconv.FormatUint(last, 10)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// every range consists of at most two boundaries, the first boundary may be the upper boundary
+	// of the cursor's range and the last boundary shows whether there are more ranges
+	count := int64(0)
+	if limit > 0 {
+		count = 2*int64(limit) + 2
+	}
+
+	bnds, err := c.boundariesFrom(ctx, min, count)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ranges, pending := pairRanges(nil, bnds)
+	if limit < 1 || (len(ranges) <= limit && pending == nil) {
+		return ranges, "", nil
+	}
+
+	ranges = ranges[:limit]
+	return ranges, strconv.FormatInt(ipToInt64(ranges[limit-1].Low), 10), nil
+}
+
+// ListRanges returns up to limit stored ranges in ascending order after skipping the first offset ranges
+// as well as the total number of stored ranges. offset and limit count ranges, not boundaries.
+// Single IP ranges consist of a single boundary, which is why the position of a range within the sorted set
+// cannot be derived from offset and all boundaries are scanned in order to count the ranges.
+// ListAllPage should be preferred for iterating over large databases.
+// A negative offset is treated like 0 and a limit < 1 returns all of the ranges after offset.
+func (c *Client) ListRanges(ctx context.Context, offset, limit int64) (ranges []RangeInfo, total int64, err error) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	all, err := c.listAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total = int64(len(all))
+	if offset >= total {
+		return []RangeInfo{}, total, nil
+	}
+
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return all[offset:end], total, nil
+}
+
+// FindByReason returns all stored ranges with the passed reason in ascending order.
+// All ranges are scanned, as there is no index of the reasons.
+func (c *Client) FindByReason(ctx context.Context, reason string) ([]RangeInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.findByReason(ctx, c.normalizeReason(reason))
+}
+
+// findByReason is the unlocked implementation of FindByReason.
+func (c *Client) findByReason(ctx context.Context, reason string) ([]RangeInfo, error) {
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RangeInfo, 0)
+	for _, r := range ranges {
+		if r.Reason == reason {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// listAll retrieves all of the stored ranges in ascending order.
+func (c *Client) listAll(ctx context.Context) ([]RangeInfo, error) {
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	ranges, _ := pairRanges(nil, bnds)
+	return ranges, nil
+}
+
+// RangeSize returns the number of IPs that the passed range contains, including its first and last IP.
+// It accepts all of the formats that Insert accepts and does not require a database connection.
+func RangeSize(ipRange string) (int64, error) {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return 0, err
+	}
+	return high.Int64 - low.Int64 + 1, nil
+}
+
+// ipToInt64 converts an IPv4 address to its integer representation.
+func ipToInt64(ip net.IP) int64 {
+	return int64(binary.BigEndian.Uint32(ip.To4()))
+}
+
+// ipFromInt64 converts the integer representation of an IPv4 address back to its net.IP representation.
+func ipFromInt64(i int64) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, uint32(i))
+	return ip
+}
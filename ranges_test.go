@@ -0,0 +1,210 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClient_All(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.0.128 - 10.0.1.0", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	got, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+
+	want := []string{
+		"10.0.0.0 - 10.0.0.127 first",
+		"10.0.0.128 - 10.0.1.0 third",
+		"10.0.1.5 - 10.0.1.5 second",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.All() = %v, want %v", got, want)
+	}
+	for idx, r := range got {
+		if s := r.Low.String() + " - " + r.High.String() + " " + r.Reason; s != want[idx] {
+			t.Errorf("rdb.All()[%d] = %s, want %s", idx, s, want[idx])
+		}
+	}
+}
+
+func TestClient_FindByReason(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "wanted"},
+		{"10.0.1.0/24", "other"},
+		{"10.0.2.5", "wanted"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	got, err := rdb.FindByReason(ctx, "wanted")
+	if err != nil {
+		t.Fatalf("rdb.FindByReason() error = %v", err)
+	}
+
+	want := []string{"10.0.0.0 - 10.0.0.255", "10.0.2.5 - 10.0.2.5"}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.FindByReason() = %v, want %v", got, want)
+	}
+	for idx, r := range got {
+		if s := r.Low.String() + " - " + r.High.String(); s != want[idx] || r.Reason != "wanted" {
+			t.Errorf("rdb.FindByReason()[%d] = %s %s, want %s wanted", idx, s, r.Reason, want[idx])
+		}
+	}
+
+	got, err = rdb.FindByReason(ctx, "missing")
+	if err != nil || len(got) != 0 {
+		t.Errorf("rdb.FindByReason() = %v, %v, want no ranges", got, err)
+	}
+}
+
+func TestRangeSize(t *testing.T) {
+	tests := []struct {
+		ipRange string
+		want    int64
+		wantErr error
+	}{
+		{"10.0.0.1", 1, nil},
+		{"10.0.0.1/32", 1, nil},
+		{"10.0.0.0/24", 256, nil},
+		{"0.0.0.0/0", 4294967296, nil},
+		{"10.0.0.0 - 10.0.0.9", 10, nil},
+		{"10.0.0.0...10.0.0.10", 10, nil},
+		{"10.0.0.9 - 10.0.0.0", 0, ErrInvalidRange},
+		{"not a range", 0, ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		got, err := RangeSize(tt.ipRange)
+		if !errors.Is(err, tt.wantErr) || got != tt.want {
+			t.Errorf("RangeSize(%q) = %d, %v, want %d, %v", tt.ipRange, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_ListRanges(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.1.6", "third"},
+		{"10.0.2.0 - 10.0.2.10", "fourth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		offset, limit int64
+		want          string
+	}{
+		{0, 2, "first,second"},
+		{1, 2, "second,third"},
+		{3, 2, "fourth"},
+		{4, 2, ""},
+		{2, 0, "third,fourth"},
+		{-1, 1, "first"},
+	}
+	for _, tt := range tests {
+		ranges, total, err := rdb.ListRanges(ctx, tt.offset, tt.limit)
+		if err != nil {
+			t.Fatalf("rdb.ListRanges() error = %v", err)
+		}
+
+		reasons := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			reasons = append(reasons, r.Reason)
+		}
+		if got := strings.Join(reasons, ","); got != tt.want || total != 4 {
+			t.Errorf("rdb.ListRanges(%d, %d) = %s, %d, want %s, 4", tt.offset, tt.limit, got, total, tt.want)
+		}
+	}
+}
+
+func TestClient_ListAllPage(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.1.6", "third"},
+		{"10.0.2.0 - 10.0.2.10", "fourth"},
+		{"10.0.3.1", "fifth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	for limit := 1; limit <= len(inserts)+1; limit++ {
+		reasons := make([]string, 0, len(inserts))
+		cursor := ""
+		for page := 0; page == 0 || cursor != ""; page++ {
+			if page > len(inserts) {
+				t.Fatalf("rdb.ListAllPage() with limit %d does not terminate", limit)
+			}
+
+			ranges, next, err := rdb.ListAllPage(ctx, cursor, limit)
+			if err != nil {
+				t.Fatalf("rdb.ListAllPage() error = %v", err)
+			}
+			if len(ranges) > limit || (next != "" && len(ranges) != limit) {
+				t.Errorf("rdb.ListAllPage() with limit %d returned %d ranges", limit, len(ranges))
+			}
+			for _, r := range ranges {
+				reasons = append(reasons, r.Reason)
+			}
+			cursor = next
+		}
+
+		if got := strings.Join(reasons, ","); got != "first,second,third,fourth,fifth" {
+			t.Errorf("rdb.ListAllPage() with limit %d returned %s", limit, got)
+		}
+	}
+
+	// changes before the cursor do not affect the following pages
+	ranges, cursor, err := rdb.ListAllPage(ctx, "", 2)
+	if err != nil || len(ranges) != 2 {
+		t.Fatalf("rdb.ListAllPage() = %v, %v", ranges, err)
+	}
+	if err := rdb.Remove(ctx, "10.0.0.0/24"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+	ranges, _, err = rdb.ListAllPage(ctx, cursor, 1)
+	if err != nil || len(ranges) != 1 || ranges[0].Reason != "third" {
+		t.Errorf("rdb.ListAllPage() = %v, %v, want third", ranges, err)
+	}
+
+	if _, _, err := rdb.ListAllPage(ctx, "invalid", 1); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("rdb.ListAllPage() error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
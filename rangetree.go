@@ -0,0 +1,109 @@
+package goripr
+
+import (
+	"fmt"
+	"net"
+	"sort"
+)
+
+// RangeTree is an immutable augmented interval tree that finds all ranges that overlap a range
+// in O(log n + k), where k is the number of overlapping ranges.
+// In contrast to the stored ranges, the ranges of a tree may overlap each other.
+//
+// The tree is implicitly balanced: the ranges are sorted by their first IP and the root of every subtree
+// is the middle range of its slice. Every node additionally stores the highest last IP of its subtree,
+// which allows to skip subtrees that end before the queried range.
+type RangeTree struct {
+	nodes []rangeTreeNode
+}
+
+type rangeTreeNode struct {
+	low, high int64
+	// maxHigh is the highest last IP of the subtree of the node
+	maxHigh int64
+	info    RangeInfo
+}
+
+// NewRangeTree creates a tree of the passed ranges, which must consist of IPv4 addresses.
+func NewRangeTree(ranges []RangeInfo) (*RangeTree, error) {
+	nodes := make([]rangeTreeNode, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Low.To4() == nil || r.High.To4() == nil {
+			return nil, fmt.Errorf("%w : range must consist of IPv4 addresses", ErrInvalidRange)
+		}
+
+		low, high := ipToInt64(r.Low), ipToInt64(r.High)
+		if low > high {
+			return nil, fmt.Errorf("%w : %s - %s", ErrInvalidRange, r.Low, r.High)
+		}
+		nodes = append(nodes, rangeTreeNode{low: low, high: high, maxHigh: high, info: r})
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].low < nodes[j].low
+	})
+
+	t := &RangeTree{nodes: nodes}
+	t.augment(0, len(nodes))
+	return t, nil
+}
+
+// augment sets the maxHigh values of the subtree of the slice nodes[lo:hi] and returns its maxHigh.
+func (t *RangeTree) augment(lo, hi int) int64 {
+	if lo >= hi {
+		return -1
+	}
+
+	mid := lo + (hi-lo)/2
+	node := &t.nodes[mid]
+	if left := t.augment(lo, mid); left > node.maxHigh {
+		node.maxHigh = left
+	}
+	if right := t.augment(mid+1, hi); right > node.maxHigh {
+		node.maxHigh = right
+	}
+	return node.maxHigh
+}
+
+// Len returns the number of ranges within the tree.
+func (t *RangeTree) Len() int {
+	return len(t.nodes)
+}
+
+// Overlaps returns all ranges of the tree that share at least one IP with the range from low to high,
+// sorted by their first IP. nil is returned if low or high is not an IPv4 address.
+func (t *RangeTree) Overlaps(low, high net.IP) []RangeInfo {
+	if low.To4() == nil || high.To4() == nil {
+		return nil
+	}
+
+	result := make([]RangeInfo, 0)
+	t.overlaps(0, len(t.nodes), ipToInt64(low), ipToInt64(high), &result)
+	return result
+}
+
+// overlaps appends the overlapping ranges of the subtree of the slice nodes[lo:hi] to result.
+func (t *RangeTree) overlaps(lo, hi int, low, high int64, result *[]RangeInfo) {
+	if lo >= hi {
+		return
+	}
+
+	mid := lo + (hi-lo)/2
+	node := &t.nodes[mid]
+	if node.maxHigh < low {
+		// every range of the subtree ends before the queried range
+		return
+	}
+
+	t.overlaps(lo, mid, low, high, result)
+
+	if node.low > high {
+		// the node and every range of the right subtree start after the queried range
+		return
+	}
+	if node.high >= low {
+		*result = append(*result, node.info)
+	}
+
+	t.overlaps(mid+1, hi, low, high, result)
+}
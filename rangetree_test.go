@@ -0,0 +1,59 @@
+package goripr
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+func TestRangeTree_Overlaps(t *testing.T) {
+	ranges := []RangeInfo{
+		{net.ParseIP("10.0.0.0"), net.ParseIP("10.0.0.255"), "first"},
+		{net.ParseIP("10.0.0.100"), net.ParseIP("10.0.0.150"), "nested"},
+		{net.ParseIP("10.0.1.5"), net.ParseIP("10.0.1.5"), "single"},
+		{net.ParseIP("9.0.0.0"), net.ParseIP("11.0.0.0"), "wide"},
+		{net.ParseIP("10.0.2.0"), net.ParseIP("10.0.2.9"), "last"},
+	}
+
+	tree, err := NewRangeTree(ranges)
+	if err != nil {
+		t.Fatalf("NewRangeTree() error = %v", err)
+	}
+	if tree.Len() != len(ranges) {
+		t.Fatalf("tree.Len() = %d, want %d", tree.Len(), len(ranges))
+	}
+
+	tests := []struct {
+		low, high string
+		want      []string
+	}{
+		{"10.0.0.120", "10.0.0.120", []string{"wide", "first", "nested"}},
+		{"10.0.0.151", "10.0.1.4", []string{"wide", "first"}},
+		{"10.0.1.5", "10.0.2.0", []string{"wide", "single", "last"}},
+		{"8.0.0.0", "8.255.255.255", []string{}},
+		{"11.0.0.0", "12.0.0.0", []string{"wide"}},
+		{"11.0.0.1", "12.0.0.0", []string{}},
+		{"0.0.0.0", "255.255.255.255", []string{"wide", "first", "nested", "single", "last"}},
+	}
+	for _, tt := range tests {
+		overlaps := tree.Overlaps(net.ParseIP(tt.low), net.ParseIP(tt.high))
+
+		got := make([]string, 0, len(overlaps))
+		for _, r := range overlaps {
+			got = append(got, r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("tree.Overlaps(%s, %s) = %v, want %v", tt.low, tt.high, got, tt.want)
+		}
+	}
+
+	if overlaps := tree.Overlaps(net.ParseIP("::1"), net.ParseIP("::2")); overlaps != nil {
+		t.Errorf("tree.Overlaps() = %v, want nil for IPv6 addresses", overlaps)
+	}
+
+	_, err = NewRangeTree([]RangeInfo{{Low: net.ParseIP("10.0.0.2"), High: net.ParseIP("10.0.0.1")}})
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("NewRangeTree() error = %v, want %v", err, ErrInvalidRange)
+	}
+}
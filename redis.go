@@ -2,11 +2,15 @@ package goripr
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"regexp"
 	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/xgfone/go-netaddr"
@@ -14,44 +18,175 @@ import (
 
 var (
 	customIPRangeRegex = regexp.MustCompile(`([0-9a-f:.]{7,41})\s*-\s*([0-9a-f:.]{7,41})`)
+
+	// exclusiveIPRangeRegex matches ranges like 10.0.0.0...10.0.0.10, where the last IP is excluded
+	exclusiveIPRangeRegex = regexp.MustCompile(`^\s*(\d{1,3}(?:\.\d{1,3}){3})\s*\.\.\.\s*(\d{1,3}(?:\.\d{1,3}){3})\s*$`)
 )
 
 // Client is an extended version of the redis.Client
 type Client struct {
-	rdb *redis.Client
+	rdb redis.UniversalClient
 	mu  sync.RWMutex
+
+	// scores of the global boundaries
+	minScore float64
+	maxScore float64
+
+	// logger logs every command if set
+	logger *slog.Logger
+
+	// reasonValidator validates every reason before it is written if set
+	reasonValidator func(reason string) error
+
+	// watchKey enables the watcher that restores the global boundaries and sets keyDeleted
+	watchKey      bool
+	watcher       *redis.PubSub
+	watcherCancel context.CancelFunc
+	keyDeleted    atomic.Bool
+
+	// skipInfBoundaries excludes the global boundaries from All
+	skipInfBoundaries bool
+
+	// lowerCaseReasons converts every reason to lower case before it is stored
+	lowerCaseReasons bool
+
+	// keepaliveInterval enables the keepalive goroutine that pings the database
+	keepaliveInterval time.Duration
+	keepaliveStop     chan struct{}
+	keepaliveDone     chan struct{}
+	keepaliveOnce     sync.Once
+
+	// insertionOrder records the first IP of every inserted range in the InsertionOrderKey list
+	insertionOrder bool
+	// insertionOrderLimit is the number of insertions that the InsertionOrderKey list keeps if it is positive
+	insertionOrderLimit int64
 }
 
 // NewClient creates a new redi client connection
-func NewClient(ctx context.Context, options Options) (*Client, error) {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:                  options.Addr,
-		Network:               options.Network,
-		ClientName:            options.ClientName,
-		Dialer:                options.Dialer,
-		OnConnect:             options.OnConnect,
-		Protocol:              options.Protocol,
-		Username:              options.Username,
-		Password:              options.Password,
-		CredentialsProvider:   options.CredentialsProvider,
-		DB:                    options.DB,
-		MaxRetries:            options.MaxRetries,
-		MinRetryBackoff:       options.MinRetryBackoff,
-		MaxRetryBackoff:       options.MaxRetryBackoff,
-		DialTimeout:           options.DialTimeout,
-		ReadTimeout:           options.ReadTimeout,
-		WriteTimeout:          options.WriteTimeout,
-		ContextTimeoutEnabled: options.ContextTimeoutEnabled,
-		PoolFIFO:              options.PoolFIFO,
-		PoolSize:              options.PoolSize,
-		PoolTimeout:           options.PoolTimeout,
-		MinIdleConns:          options.MinIdleConns,
-		MaxIdleConns:          options.MaxIdleConns,
-		ConnMaxIdleTime:       options.ConnMaxIdleTime,
-		ConnMaxLifetime:       options.ConnMaxLifetime,
-		TLSConfig:             options.TLSConfig,
-		Limiter:               options.Limiter,
-	})
+func NewClient(ctx context.Context, options Options, opts ...Option) (*Client, error) {
+	client, err := newClient(ctx, options, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.init(ctx)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("%w : %v", ErrDatabaseInit, err)
+	}
+
+	return client, nil
+}
+
+// newClient connects to the database without initializing the global boundaries.
+func newClient(ctx context.Context, options Options, opts ...Option) (*Client, error) {
+	client := &Client{
+		minScore:          math.Inf(-1),
+		maxScore:          math.Inf(+1),
+		skipInfBoundaries: true,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if !(client.minScore < client.maxScore) {
+		return nil, fmt.Errorf("%w : lower boundary %v must be smaller than upper boundary %v", ErrDatabaseInit, client.minScore, client.maxScore)
+	}
+
+	if len(options.SentinelAddrs) > 0 && len(options.ClusterAddrs) > 0 {
+		return nil, fmt.Errorf("%w : SentinelAddrs and ClusterAddrs are mutually exclusive", ErrInvalidOptions)
+	}
+
+	var rdb redis.UniversalClient
+	switch {
+	case len(options.ClusterAddrs) > 0:
+		rdb = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:                 options.ClusterAddrs,
+			ClientName:            options.ClientName,
+			Dialer:                options.Dialer,
+			OnConnect:             options.OnConnect,
+			Protocol:              options.Protocol,
+			Username:              options.Username,
+			Password:              options.Password,
+			CredentialsProvider:   options.CredentialsProvider,
+			MaxRetries:            options.MaxRetries,
+			MinRetryBackoff:       options.MinRetryBackoff,
+			MaxRetryBackoff:       options.MaxRetryBackoff,
+			DialTimeout:           options.DialTimeout,
+			ReadTimeout:           options.ReadTimeout,
+			WriteTimeout:          options.WriteTimeout,
+			ContextTimeoutEnabled: options.ContextTimeoutEnabled,
+			PoolFIFO:              options.PoolFIFO,
+			PoolSize:              options.PoolSize,
+			PoolTimeout:           options.PoolTimeout,
+			MinIdleConns:          options.MinIdleConns,
+			MaxIdleConns:          options.MaxIdleConns,
+			ConnMaxIdleTime:       options.ConnMaxIdleTime,
+			ConnMaxLifetime:       options.ConnMaxLifetime,
+			TLSConfig:             options.TLSConfig,
+		})
+	case len(options.SentinelAddrs) > 0:
+		rdb = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:            options.MasterName,
+			SentinelAddrs:         options.SentinelAddrs,
+			ClientName:            options.ClientName,
+			Dialer:                options.Dialer,
+			OnConnect:             options.OnConnect,
+			Protocol:              options.Protocol,
+			Username:              options.Username,
+			Password:              options.Password,
+			DB:                    options.DB,
+			MaxRetries:            options.MaxRetries,
+			MinRetryBackoff:       options.MinRetryBackoff,
+			MaxRetryBackoff:       options.MaxRetryBackoff,
+			DialTimeout:           options.DialTimeout,
+			ReadTimeout:           options.ReadTimeout,
+			WriteTimeout:          options.WriteTimeout,
+			ContextTimeoutEnabled: options.ContextTimeoutEnabled,
+			PoolFIFO:              options.PoolFIFO,
+			PoolSize:              options.PoolSize,
+			PoolTimeout:           options.PoolTimeout,
+			MinIdleConns:          options.MinIdleConns,
+			MaxIdleConns:          options.MaxIdleConns,
+			ConnMaxIdleTime:       options.ConnMaxIdleTime,
+			ConnMaxLifetime:       options.ConnMaxLifetime,
+			TLSConfig:             options.TLSConfig,
+		})
+	default:
+		rdb = redis.NewClient(&redis.Options{
+			Addr:                  options.Addr,
+			Network:               options.Network,
+			ClientName:            options.ClientName,
+			Dialer:                options.Dialer,
+			OnConnect:             options.OnConnect,
+			Protocol:              options.Protocol,
+			Username:              options.Username,
+			Password:              options.Password,
+			CredentialsProvider:   options.CredentialsProvider,
+			DB:                    options.DB,
+			MaxRetries:            options.MaxRetries,
+			MinRetryBackoff:       options.MinRetryBackoff,
+			MaxRetryBackoff:       options.MaxRetryBackoff,
+			DialTimeout:           options.DialTimeout,
+			ReadTimeout:           options.ReadTimeout,
+			WriteTimeout:          options.WriteTimeout,
+			ContextTimeoutEnabled: options.ContextTimeoutEnabled,
+			PoolFIFO:              options.PoolFIFO,
+			PoolSize:              options.PoolSize,
+			PoolTimeout:           options.PoolTimeout,
+			MinIdleConns:          options.MinIdleConns,
+			MaxIdleConns:          options.MaxIdleConns,
+			ConnMaxIdleTime:       options.ConnMaxIdleTime,
+			ConnMaxLifetime:       options.ConnMaxLifetime,
+			TLSConfig:             options.TLSConfig,
+			Limiter:               options.Limiter,
+		})
+	}
+
+	if client.logger != nil {
+		rdb.AddHook(debugHook{logger: client.logger})
+	}
 
 	// ping test
 	result, err := rdb.Ping(ctx).Result()
@@ -66,16 +201,19 @@ func NewClient(ctx context.Context, options Options) (*Client, error) {
 		return nil, ErrConnectionFailed
 	}
 
-	client := &Client{
-		rdb: rdb,
-	}
+	client.rdb = rdb
 
-	err = client.init(ctx)
-	if err != nil {
-		client.Close()
-		return nil, fmt.Errorf("%w : %v", ErrDatabaseInit, err)
+	if client.watchKey {
+		err = client.startKeyWatcher(ctx, options.DB)
+		if err != nil {
+			rdb.Close()
+			return nil, fmt.Errorf("%w : %v", ErrConnectionFailed, err)
+		}
 	}
 
+	if client.keepaliveInterval > 0 {
+		client.startKeepalive()
+	}
 	return client, nil
 }
 
@@ -88,22 +226,22 @@ func (c *Client) init(ctx context.Context) error {
 
 	tx.ZAdd(ctx, IPRangesKey,
 		redis.Z{
-			Score:  math.Inf(-1),
+			Score:  c.minScore,
 			Member: "-inf",
 		},
 		redis.Z{
-			Score:  math.Inf(+1),
+			Score:  c.maxScore,
 			Member: "+inf",
 		},
 	)
 
-	tx.HMSet(ctx, "-inf", map[string]interface{}{
+	tx.HMSet(ctx, negInfBoundary.Key(), map[string]interface{}{
 		"low":    false,
 		"high":   true,
 		"reason": "-inf",
 	})
 
-	tx.HMSet(ctx, "+inf", map[string]interface{}{
+	tx.HMSet(ctx, posInfBoundary.Key(), map[string]interface{}{
 		"low":    true,
 		"high":   false,
 		"reason": "+inf",
@@ -115,6 +253,11 @@ func (c *Client) init(ctx context.Context) error {
 
 // Close the redis database connection
 func (c *Client) Close() error {
+	c.stopKeepalive()
+	if c.watcher != nil {
+		c.watcherCancel()
+		c.watcher.Close()
+	}
 	return c.rdb.Close()
 }
 
@@ -123,8 +266,7 @@ func (c *Client) Flush(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	_, err := c.rdb.FlushDB(ctx).Result()
-	return err
+	return c.flushDB(ctx)
 }
 
 // Reset the database except for its global boundaries
@@ -132,14 +274,27 @@ func (c *Client) Reset(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if _, err := c.rdb.FlushDB(ctx).Result(); err != nil {
+	if err := c.flushDB(ctx); err != nil {
 		return err
 	}
 	return c.init(ctx)
 }
 
+// flushDB flushes the selected database or every master of a redis cluster.
+func (c *Client) flushDB(ctx context.Context) error {
+	if cluster, ok := c.rdb.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+			return master.FlushDB(ctx).Err()
+		})
+	}
+	return c.rdb.FlushDB(ctx).Err()
+}
+
 // all retrieves all range boundaries that are within the database.
 func (c *Client) all(ctx context.Context) (inside []boundary, err error) {
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
 
 	results, err := c.rdb.ZRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
 		Min: "-inf",
@@ -151,7 +306,7 @@ func (c *Client) all(ctx context.Context) (inside []boundary, err error) {
 	}
 
 	for _, result := range results {
-		bnd := newBoundary(result.Score, "", false, false)
+		bnd := c.boundaryOf(result)
 		inside = append(inside, bnd)
 	}
 
@@ -174,50 +329,63 @@ func (c *Client) all(ctx context.Context) (inside []boundary, err error) {
 			return nil, err
 		}
 
-		if len(result) != 3 {
-			panic(fmt.Sprintf("database inconsistent: expected 3 result attributes, got %d", len(result)))
-		}
-
-		low := false
-		switch t := result[0].(type) {
-		case string:
-			low = t == "1"
-		default:
-			low = false
+		err = inside[idx].SetAttributes(result)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		high := false
-		switch t := result[1].(type) {
-		case string:
-			high = t == "1"
-		default:
-			high = false
-		}
+	sort.Sort(byIP(inside))
+	return inside, nil
+}
 
-		reason := ""
-		switch t := result[2].(type) {
-		case string:
-			reason = t
-		default:
-			reason = ""
-		}
+// boundaryOf creates the boundary of a sorted set member without any attributes.
+func (c *Client) boundaryOf(z redis.Z) boundary {
+	var bnd boundary
+	switch z.Member {
+	case negInfBoundary.ID:
+		bnd = negInfBoundary
+	case posInfBoundary.ID:
+		bnd = posInfBoundary
+	default:
+		return newBoundary(z.Score, "", false, false)
+	}
 
-		inside[idx].LowerBound = low
-		inside[idx].UpperBound = high
-		inside[idx].Reason = reason
+	// global boundaries may have custom scores
+	if !math.IsInf(z.Score, 0) {
+		bnd.Int64 = int64(z.Score)
+		bnd.Float64 = z.Score
 	}
+	return bnd
+}
 
-	sort.Sort(byIP(inside))
-	return inside, nil
+// inBounds returns true if the IP of b lies strictly between the global boundaries.
+func (c *Client) inBounds(b boundary) bool {
+	return c.minScore < b.Float64 && b.Float64 < c.maxScore
 }
 
-// neighboursInt does not do any checks, thus making it reusable in other methods without check overhead
+// vicinity returns the num nearest boundaries below low, all boundaries from low to high and the num nearest
+// boundaries above high including their attributes.
+// Expired boundaries that have not been swept, yet, are treated as if they were not stored.
+// It does not do any checks, thus making it reusable in other methods without check overhead.
 func (c *Client) vicinity(ctx context.Context, low, high boundary, num int64) (below, inside, above []boundary, err error) {
+	below, inside, above, _, err = c.expiringVicinity(ctx, low, high, num)
+	return below, inside, above, err
+}
+
+// expiringVicinity returns the vicinity of the range like vicinity and additionally reports whether the
+// nearest boundaries at or below low and at or above high have both expired, but have not been swept, yet,
+// meaning that the range lies within an expired range.
+func (c *Client) expiringVicinity(ctx context.Context, low, high boundary, num int64) (below, inside, above []boundary, expired bool, err error) {
 
 	if num < 0 {
 		panic(fmt.Sprintf("passed num parameter must be >= 0, got %d", num))
 	}
 
+	if err := c.checkKey(); err != nil {
+		return nil, nil, nil, false, err
+	}
+
 	below = make([]boundary, 0, num)
 	inside = make([]boundary, 0, 1)
 	above = make([]boundary, 0, num)
@@ -245,18 +413,18 @@ func (c *Client) vicinity(ctx context.Context, low, high boundary, num int64) (b
 
 	_, err = tx.Exec(ctx)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		return nil, nil, nil, false, fmt.Errorf("%w : %v", ErrNoResult, err)
 	}
 
 	// transaction results of below command
 	belowResults, err := cmdBelow.Result()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		return nil, nil, nil, false, fmt.Errorf("%w : %v", ErrNoResult, err)
 	}
 
 	// create below IPs
 	for _, result := range belowResults {
-		bnd := newBoundary(result.Score, "", false, false)
+		bnd := c.boundaryOf(result)
 		below = append(below, bnd)
 	}
 
@@ -265,12 +433,12 @@ func (c *Client) vicinity(ctx context.Context, low, high boundary, num int64) (b
 
 	insideResults, err := cmdInside.Result()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		return nil, nil, nil, false, fmt.Errorf("%w : %v", ErrNoResult, err)
 	}
 
 	// create inside IPs
 	for _, result := range insideResults {
-		boundary := newBoundary(result.Score, "", false, false)
+		boundary := c.boundaryOf(result)
 		inside = append(inside, boundary)
 	}
 
@@ -278,12 +446,12 @@ func (c *Client) vicinity(ctx context.Context, low, high boundary, num int64) (b
 
 	aboveResults, err := cmdAbove.Result()
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		return nil, nil, nil, false, fmt.Errorf("%w : %v", ErrNoResult, err)
 	}
 
 	// create above IPs
 	for _, result := range aboveResults {
-		bnd := newBoundary(result.Score, "", false, false)
+		bnd := c.boundaryOf(result)
 		above = append(above, bnd)
 	}
 
@@ -296,164 +464,105 @@ func (c *Client) vicinity(ctx context.Context, low, high boundary, num int64) (b
 
 	belowAttrCmds := make([]*redis.SliceCmd, 0, len(below))
 	for _, bnd := range below {
-		belowAttrCmds = append(belowAttrCmds, tx.HMGet(ctx, bnd.ID, "low", "high", "reason"))
+		belowAttrCmds = append(belowAttrCmds, bnd.Get(ctx, tx))
 	}
 
 	insideAttrCmds := make([]*redis.SliceCmd, 0, len(inside))
 	for _, bnd := range inside {
-		insideAttrCmds = append(insideAttrCmds, tx.HMGet(ctx, bnd.ID, "low", "high", "reason"))
+		insideAttrCmds = append(insideAttrCmds, bnd.Get(ctx, tx))
 	}
 
 	aboveAttrCmds := make([]*redis.SliceCmd, 0, len(above))
 	for _, bnd := range above {
-		aboveAttrCmds = append(aboveAttrCmds, tx.HMGet(ctx, bnd.ID, "low", "high", "reason"))
+		aboveAttrCmds = append(aboveAttrCmds, bnd.Get(ctx, tx))
 	}
 
 	_, err = tx.Exec(ctx)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		return nil, nil, nil, false, fmt.Errorf("%w : %v", ErrNoResult, err)
 	}
 
-	for idx, cmd := range belowAttrCmds {
-		result, err := cmd.Result()
+	for _, attrs := range []struct {
+		bnds []boundary
+		cmds []*redis.SliceCmd
+	}{
+		{below, belowAttrCmds},
+		{inside, insideAttrCmds},
+		{above, aboveAttrCmds},
+	} {
+		err = setVicinityAttributes(attrs.bnds, attrs.cmds)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
-
-		if len(result) != 3 {
-			err = fmt.Errorf("expected 3 result attributes, got %d", len(result))
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+			return nil, nil, nil, false, err
 		}
+	}
 
-		low := false
-		switch t := result[0].(type) {
-		case string:
-			low = t == "1"
-		case nil:
-			low = false
-		default:
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, fmt.Errorf("unexpected type: %T", t))
-		}
+	// the nearest boundaries at or below low and at or above high
+	var nearestBelow, nearestAbove *boundary
+	if len(inside) > 0 && inside[0].EqualIP(low) {
+		nearestBelow = &inside[0]
+	} else if len(below) > 0 {
+		nearestBelow = &below[len(below)-1]
+	}
+	if len(inside) > 0 && inside[len(inside)-1].EqualIP(high) {
+		nearestAbove = &inside[len(inside)-1]
+	} else if len(above) > 0 {
+		nearestAbove = &above[0]
+	}
+	expired = nearestBelow != nil && nearestBelow.Expired && nearestAbove != nil && nearestAbove.Expired
 
-		high := false
-		switch t := result[1].(type) {
-		case string:
-			high = t == "1"
-		case nil:
-			high = false
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
+	fetchedBelow, fetchedAbove := len(below), len(above)
+	below = withoutExpired(below)
+	inside = withoutExpired(inside)
+	above = withoutExpired(above)
 
-		reason := ""
-		switch t := result[2].(type) {
-		case string:
-			reason = t
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	// look further for the neighbours that replace the expired ones
+	if int64(len(below)) < num && int64(fetchedBelow) == num {
+		below, err = c.nearestStored(ctx, math.Inf(-1), low.Below().Float64, true, num)
+		if err != nil {
+			return nil, nil, nil, false, err
 		}
-
-		below[idx].LowerBound = low
-		below[idx].UpperBound = high
-		below[idx].Reason = reason
+		sort.Sort(byIP(below))
 	}
-
-	for idx, cmd := range insideAttrCmds {
-		result, err := cmd.Result()
+	if int64(len(above)) < num && int64(fetchedAbove) == num {
+		above, err = c.nearestStored(ctx, high.Above().Float64, math.Inf(1), false, num)
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
-
-		if len(result) != 3 {
-			err = fmt.Errorf("expected 3 result attributes, got %d", len(result))
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+			return nil, nil, nil, false, err
 		}
+	}
 
-		low := false
-		switch t := result[0].(type) {
-		case string:
-			low = t == "1"
-		case nil:
-			low = false
-		default:
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, fmt.Errorf("unexpected type: %T", t))
-		}
+	if num > 0 && (len(below) == 0 || len(above) == 0) {
+		// only the attributes of the ±inf boundaries can be missing
+		return nil, nil, nil, false, fmt.Errorf("%w : the attributes of a global boundary are missing", ErrDatabaseInconsistent)
+	}
 
-		high := false
-		switch t := result[1].(type) {
-		case string:
-			high = t == "1"
-		case nil:
-			high = false
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
+	return below, inside, above, expired, nil
+}
 
-		reason := ""
-		switch t := result[2].(type) {
-		case string:
-			reason = t
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+// withoutExpired returns the boundaries that have not expired.
+func withoutExpired(bnds []boundary) []boundary {
+	result := bnds[:0]
+	for _, bnd := range bnds {
+		if !bnd.Expired {
+			result = append(result, bnd)
 		}
-
-		inside[idx].LowerBound = low
-		inside[idx].UpperBound = high
-		inside[idx].Reason = reason
 	}
+	return result
+}
 
-	for idx, cmd := range aboveAttrCmds {
+// setVicinityAttributes sets the attributes of the boundaries from the results of their Get commands.
+func setVicinityAttributes(bnds []boundary, cmds []*redis.SliceCmd) error {
+	for idx, cmd := range cmds {
 		result, err := cmd.Result()
 		if err != nil {
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
 		}
 
-		if len(result) != 3 {
-			err = fmt.Errorf("expected 3 result attributes, got %d", len(result))
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
-
-		low := false
-		switch t := result[0].(type) {
-		case string:
-			low = t == "1"
-		case nil:
-			low = false
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
-
-		high := false
-		switch t := result[1].(type) {
-		case string:
-			high = t == "1"
-		case nil:
-			high = false
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
-		}
-
-		reason := ""
-		switch t := result[2].(type) {
-		case string:
-			reason = t
-		default:
-			err = fmt.Errorf("unexpected type: %T", t)
-			return nil, nil, nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+		err = bnds[idx].SetAttributes(result)
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
 		}
-
-		above[idx].LowerBound = low
-		above[idx].UpperBound = high
-		above[idx].Reason = reason
 	}
-
-	return below, inside, above, nil
+	return nil
 }
 
 // Insert inserts a new IP range or IP into the database with an associated reason string
@@ -461,25 +570,73 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	reason = c.normalizeReason(reason)
+
 	low, high, err := parseRange(ipRange, reason)
 	if err != nil {
 		return err
 	}
 
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	return c.insert(ctx, low, high)
+}
+
+// insert is the unlocked implementation of Insert for already parsed boundaries.
+func (c *Client) insert(ctx context.Context, low, high boundary) error {
 	tx := c.rdb.TxPipeline()
 
+	err := c.queueInsert(ctx, tx, low, high)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// queueInsert looks at the vicinity of the range and adds all commands that are needed
+// to insert the range to tx.
+func (c *Client) queueInsert(ctx context.Context, tx redis.Pipeliner, low, high boundary) error {
 	belowN, inside, aboveN, err := c.vicinity(ctx, low, high, 1)
 	if err != nil {
 		return err
 	}
 
+	c.queueInsertWithin(ctx, tx, low, high, belowN, inside, aboveN)
+	return nil
+}
+
+// queueInsertWithin adds all commands that are needed to insert the range to tx,
+// based on the already retrieved vicinity of the range.
+func (c *Client) queueInsertWithin(ctx context.Context, tx redis.Pipeliner, low, high boundary, belowN, inside, aboveN []boundary) {
 	if len(belowN) == 0 || len(aboveN) == 0 {
 		panic(fmt.Sprintf("database inconsistent: %d below, %d above", len(belowN), len(aboveN)))
 	}
 
+	if c.insertionOrder {
+		tx.RPush(ctx, InsertionOrderKey, low.ID)
+		if c.insertionOrderLimit > 0 {
+			tx.LTrim(ctx, InsertionOrderKey, -c.insertionOrderLimit, -1)
+		}
+	}
+
+	insertWithin(c.newRedisStore(ctx, tx), low, high, belowN, inside, aboveN)
+}
+
+// insertWithin applies all boundary changes that are needed to insert the range to s,
+// based on the already retrieved vicinity of the range.
+func insertWithin(s boundaryStore, low, high boundary, belowN, inside, aboveN []boundary) {
 	// remove inside
 	for _, bnd := range inside {
-		bnd.Remove(ctx, tx)
+		s.ZRemBoundary(bnd)
 	}
 
 	belowNearest := belowN[0]
@@ -488,10 +645,12 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 	belowCut := low.Below()
 	belowCut.SetUpperBound()
 	belowCut.Reason = belowNearest.Reason
+	belowCut.SetExpiry(belowNearest)
 
 	aboveCut := high.Above()
 	aboveCut.SetLowerBound()
 	aboveCut.Reason = aboveNearest.Reason
+	aboveCut.SetExpiry(aboveNearest)
 
 	insertLowerBound := true
 	insertUpperBound := true
@@ -502,9 +661,10 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 			// can cut below |----
 			if !belowNearest.EqualReason(low) {
 				// only insert if reasons differ
-				belowCut.Insert(ctx, tx)
+				insertBoundary(s, belowCut)
 			} else {
 				// extend range towards belowNearest
+				extendBoundary(s, belowNearest, low)
 				insertLowerBound = false
 			}
 		} else {
@@ -512,15 +672,17 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 			if !belowNearest.EqualReason(low) {
 				// if reasons differ, make beLowNearest a single bound
 				belowNearest.SetDoubleBound()
-				belowNearest.Insert(ctx, tx)
+				insertBoundary(s, belowNearest)
 			} else {
+				extendBoundary(s, belowNearest, low)
 				insertLowerBound = false
 			}
 		}
 	} else if belowNearest.IsDoubleBound() && belowNearest.EqualIP(belowCut) && belowNearest.EqualReason(low) {
 		// one IP below we have a single boundary range with the same reason
 		belowNearest.SetLowerBound()
-		belowNearest.Insert(ctx, tx)
+		belowNearest.SetExpiry(low)
+		insertBoundary(s, belowNearest)
 	}
 
 	if aboveNearest.IsUpperBound() {
@@ -529,10 +691,11 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 			// can cut above -----|
 			if !aboveNearest.EqualReason(high) {
 				// insert if reasons differ
-				aboveCut.Insert(ctx, tx)
+				insertBoundary(s, aboveCut)
 			} else {
 				// don't insert, because extends range
 				// to upperbound above
+				extendBoundary(s, aboveNearest, high)
 				insertUpperBound = false
 			}
 
@@ -540,32 +703,42 @@ func (c *Client) Insert(ctx context.Context, ipRange, reason string) error {
 			// cannot cut above
 			if !aboveNearest.EqualReason(high) {
 				aboveNearest.SetDoubleBound()
-				aboveNearest.Insert(ctx, tx)
+				insertBoundary(s, aboveNearest)
 			} else {
+				extendBoundary(s, aboveNearest, high)
 				insertUpperBound = false
 			}
 		}
 	} else if aboveNearest.IsDoubleBound() && aboveNearest.EqualIP(aboveCut) && aboveNearest.EqualReason(high) {
 		// one IP above we have a single boundary range with the same reason
 		aboveNearest.SetUpperBound()
-		aboveNearest.Insert(ctx, tx)
+		aboveNearest.SetExpiry(high)
+		insertBoundary(s, aboveNearest)
 	}
 
 	if low.EqualIP(high) && insertLowerBound && insertUpperBound {
 		doubleBoundary := low
 		doubleBoundary.SetDoubleBound()
-		doubleBoundary.Insert(ctx, tx)
+		insertBoundary(s, doubleBoundary)
 	} else if insertLowerBound && insertUpperBound {
-		low.Insert(ctx, tx)
-		high.Insert(ctx, tx)
+		insertBoundary(s, low)
+		insertBoundary(s, high)
 	} else if insertLowerBound {
-		low.Insert(ctx, tx)
+		insertBoundary(s, low)
 	} else if insertUpperBound {
-		high.Insert(ctx, tx)
+		insertBoundary(s, high)
 	}
+}
 
-	_, err = tx.Exec(ctx)
-	return err
+// extendBoundary lets the stored boundary bnd, which becomes a boundary of the inserted range,
+// take over the expiry of the inserted boundary inserted. Otherwise, the boundaries of the merged range
+// would expire at different times.
+func extendBoundary(s boundaryStore, bnd, inserted boundary) {
+	if bnd.ExpiresAt == inserted.ExpiresAt {
+		return
+	}
+	bnd.SetExpiry(inserted)
+	s.SetBoundaryAttrs(bnd)
 }
 
 // Remove removes an IP range from the database.
@@ -579,37 +752,76 @@ func (c *Client) Remove(ctx context.Context, ipRange string) error {
 		return err
 	}
 
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	return c.remove(ctx, low, high)
+}
+
+// remove is the unlocked implementation of Remove for already parsed boundaries.
+func (c *Client) remove(ctx context.Context, low, high boundary) error {
 	tx := c.rdb.TxPipeline()
 
+	err := c.queueRemove(ctx, tx, low, high)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// queueRemove looks at the vicinity of the range and adds all commands that are needed
+// to remove the range to tx.
+func (c *Client) queueRemove(ctx context.Context, tx redis.Pipeliner, low, high boundary) error {
 	below, inside, above, err := c.vicinity(ctx, low, high, 1)
 	if err != nil {
 		return err
 	}
 
+	c.queueRemoveWithin(ctx, tx, low, high, below, inside, above)
+	return nil
+}
+
+// queueRemoveWithin adds all commands that are needed to remove the range to tx,
+// based on the already retrieved vicinity of the range.
+// It returns the nearest boundaries below and above the range as they are after the removal.
+func (c *Client) queueRemoveWithin(ctx context.Context, tx redis.Pipeliner, low, high boundary, below, inside, above []boundary) (belowNearest, aboveNearest boundary) {
+	return removeWithin(c.newRedisStore(ctx, tx), low, high, below, inside, above)
+}
+
+// removeWithin applies all boundary changes that are needed to remove the range to s,
+// based on the already retrieved vicinity of the range.
+// It returns the nearest boundaries below and above the range as they are after the removal.
+func removeWithin(s boundaryStore, low, high boundary, below, inside, above []boundary) (belowNearest, aboveNearest boundary) {
 	for _, bnd := range inside {
-		bnd.Remove(ctx, tx)
+		s.ZRemBoundary(bnd)
 	}
 
-	belowNearest := below[0]
-	aboveNearest := above[0]
+	belowNearest = below[0]
+	aboveNearest = above[0]
 
 	belowCut := low.Below()
 	belowCut.SetUpperBound()
 	belowCut.Reason = belowNearest.Reason
+	belowCut.SetExpiry(belowNearest)
 
 	aboveCut := high.Above()
-	aboveCut.SetUpperBound()
+	aboveCut.SetLowerBound()
 	aboveCut.Reason = aboveNearest.Reason
+	aboveCut.SetExpiry(aboveNearest)
 
 	if belowNearest.IsLowerBound() {
 		// need to cut below
 		if !belowNearest.EqualIP(belowCut) {
 			// can cut
-			belowCut.Insert(ctx, tx)
+			insertBoundary(s, belowCut)
+			belowNearest = belowCut
 		} else {
 			// cannot cut
 			belowNearest.SetDoubleBound()
-			belowNearest.Insert(ctx, tx)
+			insertBoundary(s, belowNearest)
 		}
 	}
 
@@ -617,42 +829,59 @@ func (c *Client) Remove(ctx context.Context, ipRange string) error {
 		// need to cut above
 		if !aboveNearest.EqualIP(aboveCut) {
 			// can cut above
-			aboveCut.Insert(ctx, tx)
+			insertBoundary(s, aboveCut)
+			aboveNearest = aboveCut
 		} else {
 			// cannot cut above
 			aboveNearest.SetDoubleBound()
-			aboveNearest.Insert(ctx, tx)
+			insertBoundary(s, aboveNearest)
 
 		}
 	}
 
-	_, err = tx.Exec(ctx)
-	return err
+	return belowNearest, aboveNearest
 }
 
 // Find searches for the requested IP in the database. If the IP is found within any previously inserted range,
 // the associated reason is returned. If it is not found, an error is returned instead.
 // returns a reason or either
 // ErrIPNotFound if no IP was found
+// ErrRangeExpired if the range has expired, but has not been swept, yet
 // ErrDatabaseInconsistent if the database has become inconsistent.
 func (c *Client) Find(ctx context.Context, ip string) (reason string, err error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	return c.find(ctx, ip)
+}
+
+// IPCovered returns true if the IP is contained in any range and false if it is not.
+// In contrast to Find, ErrIPNotFound is not returned as an error.
+func (c *Client) IPCovered(ctx context.Context, ip string) (bool, error) {
+	_, err := c.Find(ctx, ip)
+	if errors.Is(err, ErrIPNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// find is the unlocked implementation of Find.
+func (c *Client) find(ctx context.Context, ip string) (reason string, err error) {
 	ipaddr, err := netaddr.NewIPAddress(ip, 4)
 	if err != nil {
 		return "", fmt.Errorf("%w : %v", ErrInvalidIP, err)
 	}
 	bnd := newBoundary(ipaddr.IP(), "", true, true)
 
-	below, inside, above, err := c.vicinity(ctx, bnd, bnd, 1)
-	if err != nil {
-		return "", err
+	if !c.inBounds(bnd) {
+		return "", ErrIPNotFound
 	}
 
-	if len(inside) == 1 {
-		found := inside[0]
-		return found.Reason, nil
+	below, inside, above, expired, err := c.expiringVicinity(ctx, bnd, bnd, 1)
+	if err != nil {
+		return "", err
 	}
 
 	if len(below) == 0 || len(above) == 0 {
@@ -660,6 +889,20 @@ func (c *Client) Find(ctx context.Context, ip string) (reason string, err error)
 		return "", ErrDatabaseInconsistent
 	}
 
+	reason, err = reasonWithin(below, inside, above)
+	if errors.Is(err, ErrIPNotFound) && expired {
+		return "", ErrRangeExpired
+	}
+	return reason, err
+}
+
+// reasonWithin returns the reason of the range that contains the IP whose vicinity is passed.
+func reasonWithin(below, inside, above []boundary) (reason string, err error) {
+	if len(inside) == 1 {
+		found := inside[0]
+		return found.Reason, nil
+	}
+
 	belowNearest := below[0]
 	aboveNearest := above[0]
 
@@ -667,7 +910,7 @@ func (c *Client) Find(ctx context.Context, ip string) (reason string, err error)
 		if belowNearest.EqualReason(aboveNearest) {
 			return belowNearest.Reason, nil
 		}
-		panic(fmt.Sprintf("reasons inconsistent: %s != %s", belowNearest.Reason, aboveNearest.Reason))
+		return "", fmt.Errorf("%w : reasons inconsistent: %s != %s", ErrDatabaseInconsistent, belowNearest.Reason, aboveNearest.Reason)
 	}
 
 	return "", ErrIPNotFound
@@ -690,6 +933,26 @@ func parseRange(r, reason string) (low, high boundary, err error) {
 	// parsing as cidr failed x.x.x.x/24
 
 	var dummy boundary
+	if matches := exclusiveIPRangeRegex.FindStringSubmatch(r); len(matches) == 3 {
+		lowIP, err := netaddr.NewIPAddress(matches[1], 4)
+		if err != nil {
+			return dummy, dummy, fmt.Errorf("%w : %v", ErrInvalidRange, err)
+		}
+		highIP, err := netaddr.NewIPAddress(matches[2], 4)
+		if err != nil {
+			return dummy, dummy, fmt.Errorf("%w : %v", ErrInvalidRange, err)
+		}
+
+		// the last IP is exclusive, thus the range must contain at least one IP
+		if lowIP.Compare(highIP) >= 0 {
+			return dummy, dummy, ErrInvalidRange
+		}
+
+		low = newBoundary(lowIP.IP(), reason, true, false)
+		high = newBoundary(highIP.BigInt().Int64()-1, reason, false, true)
+		return low, high, nil
+	}
+
 	if matches := customIPRangeRegex.FindStringSubmatch(r); len(matches) == 3 {
 		lowerBound := matches[1]
 		upperBound := matches[2]
@@ -718,6 +981,7 @@ func parseRange(r, reason string) (low, high boundary, err error) {
 type UpdateFunc func(oldReason string) (newReason string)
 
 // UpdateReasonOf updates the reason of the range that contains the passed ip.
+// fn is called once with the current reason of that range.
 func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -728,6 +992,10 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 	}
 	bnd := newBoundary(ipaddr.IP(), "", true, true)
 
+	if !c.inBounds(bnd) {
+		return ErrIPNotFound
+	}
+
 	below, inside, above, err := c.vicinity(ctx, bnd, bnd, 1)
 	if err != nil {
 		return err
@@ -737,6 +1005,17 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 	belowNearest := below[0]
 	aboveNearest := above[0]
 
+	// the first invalid reason returned by fn prevents the update
+	var validationErr error
+	update := fn
+	fn = func(oldReason string) string {
+		newReason := c.normalizeReason(update(oldReason))
+		if validationErr == nil {
+			validationErr = c.validateReason(newReason)
+		}
+		return newReason
+	}
+
 	tx := c.rdb.TxPipeline()
 
 	if len(inside) == 1 {
@@ -756,7 +1035,7 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 				found.Update(ctx, tx)
 
 				// upper bound
-				aboveNearest.Reason = fn(aboveNearest.Reason)
+				aboveNearest.Reason = found.Reason
 				aboveNearest.Update(ctx, tx)
 			} else {
 				panic(fmt.Sprintf("database inconsistent: found two lower bounds: %s, %s", found.IP, aboveNearest.IP))
@@ -766,7 +1045,7 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 			if belowNearest.IsLowerBound() {
 
 				// lower bound
-				belowNearest.Reason = fn(aboveNearest.Reason)
+				belowNearest.Reason = found.Reason
 				belowNearest.Update(ctx, tx)
 
 				// upper bound
@@ -776,6 +1055,10 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 			}
 		}
 
+		if validationErr != nil {
+			return validationErr
+		}
+
 		_, err = tx.Exec(ctx)
 		return err
 	}
@@ -786,11 +1069,15 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 	if belowNearest.IsLowerBound() && aboveNearest.IsUpperBound() {
 		if belowNearest.EqualReason(aboveNearest) {
 			belowNearest.Reason = fn(belowNearest.Reason)
-			aboveNearest.Reason = fn(aboveNearest.Reason)
+			aboveNearest.Reason = belowNearest.Reason
 
 			belowNearest.Update(ctx, tx)
 			aboveNearest.Update(ctx, tx)
 
+			if validationErr != nil {
+				return validationErr
+			}
+
 			_, err = tx.Exec(ctx)
 			return err
 		}
@@ -800,6 +1087,388 @@ func (c *Client) UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) (
 	return ErrIPNotFound
 }
 
+// InsertOrUpdate updates the reason of the passed range if exactly that range is already stored
+// and inserts it like Insert otherwise. Both cases require a single lookup of the range's vicinity
+// and a single transaction.
+func (c *Client) InsertOrUpdate(ctx context.Context, ipRange, reason string) error {
+	reason = c.normalizeReason(reason)
+
+	low, high, err := parseRange(ipRange, reason)
+	if err != nil {
+		return err
+	}
+
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	below, inside, above, err := c.vicinity(ctx, low, high, 1)
+	if err != nil {
+		return err
+	}
+
+	tx := c.rdb.TxPipeline()
+	if lowBnd, highBnd, ok := exactBoundaries(low, high, inside); ok {
+		c.queueReasonUpdate(ctx, tx, lowBnd, highBnd, reason)
+	} else {
+		c.queueInsertWithin(ctx, tx, low, high, below, inside, above)
+	}
+
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// InsertIfNoOverlap inserts the range like Insert, but only if it does not share any IP with the stored ranges.
+// Otherwise an *OverlapError is returned, which wraps ErrRangeOverlap and contains the overlapping ranges.
+func (c *Client) InsertIfNoOverlap(ctx context.Context, ipRange, reason string) error {
+	reason = c.normalizeReason(reason)
+
+	low, high, err := parseRange(ipRange, reason)
+	if err != nil {
+		return err
+	}
+
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	overlapping, err := c.overlapping(ctx, low, high)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return &OverlapError{Ranges: overlapping}
+	}
+
+	return c.insert(ctx, low, high)
+}
+
+// ReplaceRange replaces the range oldRange with the range newRange within a single transaction.
+// Both ranges must overlap or be adjacent to each other, e.g. when a CIDR range is extended by one bit.
+// Every other client either sees the old or the new range, but never a state in which neither of them is stored.
+// All stored IPs of oldRange that are not part of newRange are removed, no matter which reason they have.
+func (c *Client) ReplaceRange(ctx context.Context, oldRange, newRange, reason string) error {
+	oldLow, oldHigh, err := parseRange(oldRange, "")
+	if err != nil {
+		return err
+	}
+
+	reason = c.normalizeReason(reason)
+
+	low, high, err := parseRange(newRange, reason)
+	if err != nil {
+		return err
+	}
+
+	if !c.inBounds(oldLow) || !c.inBounds(oldHigh) || !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	if !(parsedRange{oldLow, oldHigh}).touches(parsedRange{low, high}) {
+		return fmt.Errorf("%w : old and new range must overlap or be adjacent", ErrInvalidRange)
+	}
+
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	// union of both ranges
+	unionLow, unionHigh := oldLow, oldHigh
+	if low.Int64 < unionLow.Int64 {
+		unionLow = low
+	}
+	if high.Int64 > unionHigh.Int64 {
+		unionHigh = high
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	below, inside, above, err := c.vicinity(ctx, unionLow, unionHigh, 1)
+	if err != nil {
+		return err
+	}
+
+	if len(below) == 0 || len(above) == 0 {
+		return ErrDatabaseInconsistent
+	}
+
+	// the union is empty after its removal, which is why the nearest boundaries
+	// of the union are the nearest boundaries of the new range as well
+	tx := c.rdb.TxPipeline()
+	belowNearest, aboveNearest := c.queueRemoveWithin(ctx, tx, unionLow, unionHigh, below, inside, above)
+	c.queueInsertWithin(ctx, tx, low, high, []boundary{belowNearest}, nil, []boundary{aboveNearest})
+
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// SplitRange splits the stored range that contains splitAt into the range from its first IP up to splitAt
+// with the reason lowerReason and the range from the IP after splitAt up to its last IP with the reason upperReason.
+// Both ranges are stored within a single transaction and expire together with the split range.
+// Each part is merged with an adjacent range that has the same reason and expiry like MergeAdjacentSameReason does.
+// ErrIPNotFound is returned if no range contains splitAt and ErrInvalidRange if splitAt is the last IP of the range
+// or if both reasons are equal, as that would leave two adjacent ranges with the same reason.
+func (c *Client) SplitRange(ctx context.Context, splitAt, lowerReason, upperReason string) error {
+	ipaddr, err := netaddr.NewIPAddress(splitAt, 4)
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	split := newBoundary(ipaddr.IP(), "", true, true)
+
+	if !c.inBounds(split) {
+		return ErrIPNotFound
+	}
+
+	lowerReason = c.normalizeReason(lowerReason)
+	upperReason = c.normalizeReason(upperReason)
+
+	if lowerReason == upperReason {
+		return fmt.Errorf("%w : both parts of the split range would have the same reason", ErrInvalidRange)
+	}
+
+	for _, reason := range []string{lowerReason, upperReason} {
+		err = c.validateReason(reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, err := c.containingRange(ctx, split)
+	if err != nil {
+		return err
+	}
+
+	if split.Int64 == ipToInt64(r.High) {
+		return fmt.Errorf("%w : cannot split a range at its last IP", ErrInvalidRange)
+	}
+
+	// the vicinity of the split range contains its boundaries and the nearest boundaries of the adjacent ranges
+	below, inside, above, err := c.vicinity(ctx, newBoundary(r.Low, "", true, false), newBoundary(r.High, "", false, true), 1)
+	if err != nil {
+		return err
+	}
+
+	if len(below) == 0 || len(inside) == 0 || len(above) == 0 {
+		return ErrDatabaseInconsistent
+	}
+
+	// the boundaries of the range below, of both parts and of the range above in ascending order.
+	// Both parts keep the expiry of the lower boundary of the split range.
+	bnds := []boundary{below[0]}
+	appendPart := func(low, high int64, reason string) {
+		lowBnd := newBoundary(low, reason, true, low == high)
+		lowBnd.SetExpiry(inside[0])
+		bnds = append(bnds, lowBnd)
+		if low != high {
+			highBnd := newBoundary(high, reason, false, true)
+			highBnd.SetExpiry(inside[0])
+			bnds = append(bnds, highBnd)
+		}
+	}
+	appendPart(ipToInt64(r.Low), split.Int64, lowerReason)
+	appendPart(split.Int64+1, ipToInt64(r.High), upperReason)
+	bnds = append(bnds, above[0])
+
+	// like MergeAdjacentSameReason, both parts are merged with adjacent ranges that have the same reason
+	merged := make([]bool, len(bnds))
+	for idx := 1; idx < len(bnds); idx++ {
+		prev, cur := &bnds[idx-1], &bnds[idx]
+		if mergeable(*prev, *cur) {
+			prev.UpperBound = false
+			cur.LowerBound = false
+			merged[idx-1] = true
+			merged[idx] = true
+		}
+	}
+
+	// the boundaries of both parts overwrite the boundaries of the split range
+	tx := c.rdb.TxPipeline()
+	for idx, bnd := range bnds {
+		isPart := idx > 0 && idx < len(bnds)-1
+		if !isPart && !merged[idx] {
+			continue
+		}
+
+		if !bnd.LowerBound && !bnd.UpperBound {
+			bnd.Remove(ctx, tx)
+		} else {
+			bnd.Insert(ctx, tx)
+		}
+	}
+
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// UpdateReasonOfRange updates the reason of exactly the passed range.
+// In contrast to UpdateReasonOf the range is not looked up by an IP within it.
+// ErrIPNotFound is returned if the range is not stored as is and ErrInvalidRange if it exceeds the global boundaries.
+func (c *Client) UpdateReasonOfRange(ctx context.Context, ipRange string, fn UpdateFunc) error {
+	low, high, err := parseRange(ipRange, "")
+	if err != nil {
+		return err
+	}
+
+	if !c.inBounds(low) || !c.inBounds(high) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	lowBnd, highBnd, ok, err := c.exactRange(ctx, low, high)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrIPNotFound
+	}
+
+	reason := c.normalizeReason(fn(lowBnd.Reason))
+	err = c.validateReason(reason)
+	if err != nil {
+		return err
+	}
+
+	tx := c.rdb.TxPipeline()
+	c.queueReasonUpdate(ctx, tx, lowBnd, highBnd, reason)
+	_, err = tx.Exec(ctx)
+	return err
+}
+
+// queueReasonUpdate adds the commands that update the reason of the range from lowBnd to highBnd to tx.
+func (c *Client) queueReasonUpdate(ctx context.Context, tx redis.Pipeliner, lowBnd, highBnd boundary, reason string) {
+	lowBnd.Reason = reason
+	lowBnd.Update(ctx, tx)
+	if !lowBnd.EqualIP(highBnd) {
+		highBnd.Reason = reason
+		highBnd.Update(ctx, tx)
+	}
+}
+
+// RenameReason replaces the reason of all boundaries that have the reason oldReason with newReason
+// within a single transaction and returns the number of updated boundaries.
+// Ranges that end up adjacent to other ranges with the reason newReason are not merged.
+// ErrIPNotFound is returned if no boundary has the reason oldReason.
+func (c *Client) RenameReason(ctx context.Context, oldReason, newReason string) (int, error) {
+	oldReason = c.normalizeReason(oldReason)
+	newReason = c.normalizeReason(newReason)
+
+	err := c.validateReason(newReason)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	tx := c.rdb.TxPipeline()
+	updated := 0
+	for _, bnd := range bnds {
+		if bnd.IsInfBound() || bnd.Reason != oldReason {
+			continue
+		}
+		tx.HSet(ctx, bnd.Key(), "reason", newReason)
+		updated++
+	}
+
+	if updated == 0 {
+		return 0, ErrIPNotFound
+	}
+
+	_, err = tx.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+// MergeAdjacentSameReason merges all ranges that are directly adjacent to each other and have the same reason
+// within a single transaction and returns the number of merges. A merge combines two ranges, which is why
+// three adjacent ranges with the same reason are counted as two merges.
+// Ranges that expire at different times are not merged.
+func (c *Client) MergeAdjacentSameReason(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	merges := 0
+	modified := make([]bool, len(bnds))
+	for idx := 1; idx < len(bnds); idx++ {
+		prev, cur := &bnds[idx-1], &bnds[idx]
+		if mergeable(*prev, *cur) {
+			prev.UpperBound = false
+			cur.LowerBound = false
+			modified[idx-1] = true
+			modified[idx] = true
+			merges++
+		}
+	}
+
+	if merges == 0 {
+		return 0, nil
+	}
+
+	tx := c.rdb.TxPipeline()
+	for idx, bnd := range bnds {
+		if !modified[idx] {
+			continue
+		}
+
+		if !bnd.LowerBound && !bnd.UpperBound {
+			bnd.Remove(ctx, tx)
+		} else {
+			bnd.Update(ctx, tx)
+		}
+	}
+
+	_, err = tx.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return merges, nil
+}
+
+// mergeable returns true if the range that ends with prev ends right before the range that starts with cur
+// and both of them have the same reason and expiry.
+func mergeable(prev, cur boundary) bool {
+	if prev.IsInfBound() || cur.IsInfBound() {
+		return false
+	}
+	return prev.UpperBound && cur.LowerBound && prev.Int64+1 == cur.Int64 && prev.EqualReason(cur) &&
+		prev.ExpiresAt == cur.ExpiresAt
+}
+
 func (rdb *Client) consistent(ctx context.Context, ipRange ...string) error {
 	ipr := ""
 	if len(ipRange) > 0 {
@@ -811,9 +1480,6 @@ func (rdb *Client) consistent(ctx context.Context, ipRange ...string) error {
 		panic(err)
 	}
 
-	const LowerBound = 0
-	const UpperBound = 1
-
 	if ipr != "" {
 		low, high, err := parseRange(ipr, "")
 		if err != nil {
@@ -840,39 +1506,8 @@ func (rdb *Client) consistent(ctx context.Context, ipRange ...string) error {
 		}
 	}
 
-	cnt := 0
-	state := LowerBound
-	for idx, attr := range attributes {
-
-		if attr.LowerBound && attr.UpperBound {
-			if state != UpperBound {
-				return fmt.Errorf("database inconsistent: double boundary: idx=%d state=%d, expected state=%d", idx, state, UpperBound)
-			}
-
-			cnt += 2
-		} else if attr.LowerBound {
-			if state != UpperBound {
-				return fmt.Errorf("database inconsistent: lower boundary: idx=%d state=%d, expected state=%d", idx, state, UpperBound)
-			}
-			cnt++
-			state = cnt % 2
-		} else if attr.UpperBound {
-			if state != LowerBound {
-				return fmt.Errorf("database inconsistent: upper boundary: idx=%d state=%d, expected state=%d", idx, state, LowerBound)
-			}
-
-			// reasons consistent
-			if idx > 0 && attr.Reason != attributes[idx-1].Reason {
-				return fmt.Errorf("reason mismatch: idx=%4d reason=%q idx=%4d reason=%q", idx-1, attributes[idx-1].Reason, idx, attr.Reason)
-			}
-
-			cnt++
-			state = cnt % 2
-		}
-	}
-
-	if state != LowerBound {
-		return fmt.Errorf("database inconsistent: final boundary is supposed to be a lower boundary")
+	if errs := consistencyErrors(attributes); len(errs) > 0 {
+		return errors.New(errs[0])
 	}
 
 	return nil
@@ -3,7 +3,10 @@ package goripr
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -17,6 +20,19 @@ type Options struct {
 	// host:port address.
 	Addr string
 
+	// SentinelAddrs are the host:port addresses of the redis sentinels.
+	// If set, the client connects to the current master of MasterName via the sentinels
+	// and Network, Addr, CredentialsProvider as well as Limiter are ignored.
+	SentinelAddrs []string
+	// MasterName is the name of the master that is monitored by the sentinels.
+	MasterName string
+
+	// ClusterAddrs are the host:port addresses of the seed nodes of a redis cluster.
+	// If set, the client connects to the cluster and Network, Addr, DB as well as Limiter are ignored.
+	// All keys share the hash tag {goripr}, which is why all ranges are stored on a single shard.
+	// ClusterAddrs must not be combined with SentinelAddrs.
+	ClusterAddrs []string
+
 	// ClientName will execute the `CLIENT SETNAME ClientName` command for each conn.
 	ClientName string
 
@@ -116,3 +132,170 @@ type Options struct {
 	// Limiter interface used to implement circuit breaker or rate limiter.
 	Limiter redis.Limiter
 }
+
+// splitAddrs splits the comma separated addresses. The empty string yields no addresses.
+func splitAddrs(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	addrs := strings.Split(value, ",")
+	for idx := range addrs {
+		addrs[idx] = strings.TrimSpace(addrs[idx])
+	}
+	return addrs
+}
+
+// envField is a single Key=Value pair of the text representation of the Options.
+type envField struct {
+	key   string
+	value string
+}
+
+// envFields returns all of the Options that can be represented as text in the order of their declaration.
+func (o Options) envFields() []envField {
+	return []envField{
+		{"Network", o.Network},
+		{"Addr", o.Addr},
+		{"SentinelAddrs", strings.Join(o.SentinelAddrs, ",")},
+		{"MasterName", o.MasterName},
+		{"ClusterAddrs", strings.Join(o.ClusterAddrs, ",")},
+		{"ClientName", o.ClientName},
+		{"Protocol", strconv.Itoa(o.Protocol)},
+		{"Username", o.Username},
+		{"Password", o.Password},
+		{"DB", strconv.Itoa(o.DB)},
+		{"MaxRetries", strconv.Itoa(o.MaxRetries)},
+		{"MinRetryBackoff", o.MinRetryBackoff.String()},
+		{"MaxRetryBackoff", o.MaxRetryBackoff.String()},
+		{"DialTimeout", o.DialTimeout.String()},
+		{"ReadTimeout", o.ReadTimeout.String()},
+		{"WriteTimeout", o.WriteTimeout.String()},
+		{"ContextTimeoutEnabled", strconv.FormatBool(o.ContextTimeoutEnabled)},
+		{"PoolFIFO", strconv.FormatBool(o.PoolFIFO)},
+		{"PoolSize", strconv.Itoa(o.PoolSize)},
+		{"PoolTimeout", o.PoolTimeout.String()},
+		{"MinIdleConns", strconv.Itoa(o.MinIdleConns)},
+		{"MaxIdleConns", strconv.Itoa(o.MaxIdleConns)},
+		{"ConnMaxIdleTime", o.ConnMaxIdleTime.String()},
+		{"ConnMaxLifetime", o.ConnMaxLifetime.String()},
+	}
+}
+
+// MarshalEnv serializes all of the Options that can be represented as text as Key=Value lines,
+// e.g. Addr=localhost:6379. Durations are formatted like time.Duration.String() and the addresses
+// of SentinelAddrs and ClusterAddrs are separated by commas.
+// Function, interface and TLS settings are not serialized. The Password is serialized as well,
+// which is why the text must be stored like any other secret. String redacts it for logging.
+func (o Options) MarshalEnv() ([]byte, error) {
+	var sb strings.Builder
+	for _, f := range o.envFields() {
+		if strings.ContainsAny(f.value, "\r\n") {
+			return nil, fmt.Errorf("%w : value of %s must not contain line breaks", ErrInvalidOptions, f.key)
+		}
+		sb.WriteString(f.key)
+		sb.WriteByte('=')
+		sb.WriteString(f.value)
+		sb.WriteByte('\n')
+	}
+	return []byte(sb.String()), nil
+}
+
+// String returns the Options like MarshalEnv as a single line of space separated Key=Value pairs,
+// but redacts a non-empty Password, which allows to log the Options.
+func (o Options) String() string {
+	fields := o.envFields()
+	pairs := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.key == "Password" && f.value != "" {
+			f.value = "REDACTED"
+		}
+		pairs = append(pairs, f.key+"="+f.value)
+	}
+	return strings.Join(pairs, " ")
+}
+
+// ParseEnv parses Key=Value lines as produced by MarshalEnv.
+// Empty lines and lines starting with # are ignored, keys that are not part of
+// the text do not modify the corresponding fields.
+func (o *Options) ParseEnv(text []byte) error {
+	for idx, line := range strings.Split(string(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("%w : line %d: missing '=': %s", ErrInvalidOptions, idx+1, line)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		err := o.set(key, value)
+		if err != nil {
+			return fmt.Errorf("%w : line %d: %v", ErrInvalidOptions, idx+1, err)
+		}
+	}
+	return nil
+}
+
+// set parses value and assigns it to the field with the name key.
+func (o *Options) set(key, value string) (err error) {
+	switch key {
+	case "Network":
+		o.Network = value
+	case "Addr":
+		o.Addr = value
+	case "SentinelAddrs":
+		o.SentinelAddrs = splitAddrs(value)
+	case "MasterName":
+		o.MasterName = value
+	case "ClusterAddrs":
+		o.ClusterAddrs = splitAddrs(value)
+	case "ClientName":
+		o.ClientName = value
+	case "Protocol":
+		o.Protocol, err = strconv.Atoi(value)
+	case "Username":
+		o.Username = value
+	case "Password":
+		o.Password = value
+	case "DB":
+		o.DB, err = strconv.Atoi(value)
+	case "MaxRetries":
+		o.MaxRetries, err = strconv.Atoi(value)
+	case "MinRetryBackoff":
+		o.MinRetryBackoff, err = time.ParseDuration(value)
+	case "MaxRetryBackoff":
+		o.MaxRetryBackoff, err = time.ParseDuration(value)
+	case "DialTimeout":
+		o.DialTimeout, err = time.ParseDuration(value)
+	case "ReadTimeout":
+		o.ReadTimeout, err = time.ParseDuration(value)
+	case "WriteTimeout":
+		o.WriteTimeout, err = time.ParseDuration(value)
+	case "ContextTimeoutEnabled":
+		o.ContextTimeoutEnabled, err = strconv.ParseBool(value)
+	case "PoolFIFO":
+		o.PoolFIFO, err = strconv.ParseBool(value)
+	case "PoolSize":
+		o.PoolSize, err = strconv.Atoi(value)
+	case "PoolTimeout":
+		o.PoolTimeout, err = time.ParseDuration(value)
+	case "MinIdleConns":
+		o.MinIdleConns, err = strconv.Atoi(value)
+	case "MaxIdleConns":
+		o.MaxIdleConns, err = strconv.Atoi(value)
+	case "ConnMaxIdleTime":
+		o.ConnMaxIdleTime, err = time.ParseDuration(value)
+	case "ConnMaxLifetime":
+		o.ConnMaxLifetime, err = time.ParseDuration(value)
+	default:
+		return fmt.Errorf("unknown key: %s", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package goripr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptions_MarshalEnv(t *testing.T) {
+	want := Options{
+		Addr:            "localhost:6379",
+		SentinelAddrs:   []string{"sentinel-1:26379", "sentinel-2:26379"},
+		MasterName:      "mymaster",
+		ClusterAddrs:    []string{"node-1:6379"},
+		Username:        "user",
+		Password:        "pass=word",
+		DB:              3,
+		MaxRetries:      -1,
+		DialTimeout:     2 * time.Second,
+		ReadTimeout:     -1,
+		PoolFIFO:        true,
+		ConnMaxIdleTime: 30 * time.Minute,
+	}
+
+	text, err := want.MarshalEnv()
+	if err != nil {
+		t.Fatalf("Options.MarshalEnv() error = %v", err)
+	}
+
+	var got Options
+	err = got.ParseEnv(text)
+	if err != nil {
+		t.Fatalf("Options.ParseEnv() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Options.ParseEnv() = %#v, want %#v", got, want)
+	}
+
+	if s := want.String(); strings.Contains(s, want.Password) || !strings.Contains(s, "Addr=localhost:6379") {
+		t.Errorf("Options.String() = %q, want the options without the password", s)
+	}
+}
+
+func TestOptions_ParseEnv(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Options
+		wantErr bool
+	}{
+		{"# comment\n\nAddr = localhost:6379\nDB=1\n", Options{Addr: "localhost:6379", DB: 1}, false},
+		{"Addr=localhost:6379\nPassword=secret\n", Options{Addr: "localhost:6379", Password: "secret"}, false},
+		{"Addr", Options{}, true},
+		{"DB=one", Options{}, true},
+		{"Unknown=1", Options{}, true},
+	}
+	for _, tt := range tests {
+		var got Options
+		err := got.ParseEnv([]byte(tt.text))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Options.ParseEnv(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("Options.ParseEnv(%q) error = %v, want %v", tt.text, err, ErrInvalidOptions)
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Options.ParseEnv(%q) = %#v, want %#v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestOptions_UnmarshalJSON(t *testing.T) {
+	var got Options
+	err := json.Unmarshal([]byte(`{"Addr":"localhost:6379","DB":2,"Password":"secret"}`), &got)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	want := Options{Addr: "localhost:6379", DB: 2, Password: "secret"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("json.Unmarshal() = %#v, want %#v", got, want)
+	}
+}
+
+func TestValidateOptionsJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		wantErr error
+	}{
+		{`{"Addr": "localhost:6379"}`, nil},
+		{`{"Addr": "localhost:6379", "Network": "unix", "Protocol": 2, "DB": 15, "MaxRetries": -1, "DialTimeout": "1.5s", "PoolFIFO": true}`, nil},
+		{`{"Network": "tcp"}`, ErrInvalidOptions},
+		{`{"Addr": ""}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "DB": 16}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "DB": 1.5}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "DB": "1"}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "Network": "udp"}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "Protocol": 4}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "DialTimeout": "5"}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "PoolFIFO": "true"}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "TLSConfig": {}}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "SentinelAddrs": ["sentinel:26379"], "MasterName": "mymaster"}`, nil},
+		{`{"Addr": "localhost:6379", "SentinelAddrs": "sentinel:26379"}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "SentinelAddrs": [""]}`, ErrInvalidOptions},
+		{`{"Addr": "localhost:6379", "ClusterAddrs": ["node-1:6379", "node-2:6379"]}`, nil},
+		{`{"SentinelAddrs": ["sentinel:26379"], "MasterName": "mymaster"}`, nil},
+		{`{"SentinelAddrs": ["sentinel:26379"]}`, ErrInvalidOptions},
+		{`{"SentinelAddrs": []}`, ErrInvalidOptions},
+		{`{"ClusterAddrs": ["node-1:6379"]}`, nil},
+		{`{"ClusterAddrs": []}`, ErrInvalidOptions},
+		{`{"SentinelAddrs": ["sentinel:26379"], "MasterName": "mymaster", "ClusterAddrs": ["node-1:6379"]}`, ErrInvalidOptions},
+		{`["localhost:6379"]`, ErrInvalidOptions},
+		{`null`, ErrInvalidOptions},
+	}
+	for _, tt := range tests {
+		if err := ValidateOptionsJSON([]byte(tt.json)); !errors.Is(err, tt.wantErr) {
+			t.Errorf("ValidateOptionsJSON(%s) error = %v, want %v", tt.json, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		schema  string
+		wantErr bool
+	}{
+		{`{"type": "object", "properties": {"Addr": {"type": "string", "minLength": 1}}}`, false},
+		{`{"type": "object", "properties": {"Addr": {"type": "string", "pattern": "^x"}}}`, true},
+		{`{"type": "object", "properties": {"Addr": {"type": "string", "maxLength": 10}}}`, true},
+		{`{"type": "object", "oneOf": [{"required": ["Addr"]}]}`, true},
+		{`{"type": "object", "not": {"required": ["Addr"], "minProperties": 1}}`, true},
+		{`{"type": "object", "properties": {"Addrs": {"type": "array", "items": {"type": "string", "pattern": "^x"}}}}`, true},
+		{`{"type": "object", "properties": {"DB": {"type": "number"}}}`, true},
+		{`{"type": "object", "properties": {"Addr": {"type": "string", "format": "hostname"}}}`, true},
+		{`{"type": "object", "properties": {"DB": {"type": "integer", "minLength": 1}}}`, true},
+		{`{"type": "array"}`, true},
+	}
+	for _, tt := range tests {
+		if _, err := parseSchema([]byte(tt.schema)); (err != nil) != tt.wantErr {
+			t.Errorf("parseSchema(%s) error = %v, wantErr %v", tt.schema, err, tt.wantErr)
+		}
+	}
+
+	if _, err := parseSchema(optionsSchema); err != nil {
+		t.Errorf("parseSchema(options.schema.json) error = %v", err)
+	}
+}
+
+func TestNewClient_SentinelUnreachable(t *testing.T) {
+	// no sentinel is listening on the address, which fails the ping of the failover client
+	_, err := NewClient(context.TODO(), Options{
+		SentinelAddrs: []string{"127.0.0.1:1"},
+		MasterName:    "mymaster",
+		DialTimeout:   time.Second,
+		MaxRetries:    -1,
+	})
+	if !errors.Is(err, ErrConnectionFailed) {
+		t.Errorf("NewClient() error = %v, want %v", err, ErrConnectionFailed)
+	}
+}
+
+func TestNewClient_SentinelAndCluster(t *testing.T) {
+	_, err := NewClient(context.TODO(), Options{
+		SentinelAddrs: []string{"127.0.0.1:1"},
+		ClusterAddrs:  []string{"127.0.0.1:2"},
+	})
+	if !errors.Is(err, ErrInvalidOptions) {
+		t.Errorf("NewClient() error = %v, want %v", err, ErrInvalidOptions)
+	}
+}
@@ -2,13 +2,17 @@ package goripr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"os"
 	"regexp"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/xgfone/go-netaddr"
 )
 
@@ -112,27 +116,29 @@ func TestClient_Insert(t *testing.T) {
 		})
 	}
 
-	for idx, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			rdb := initRDB(0)
-			defer rdb.Close()
+	for _, store := range rangeStores {
+		for idx, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+				rdb := store.new()
+				defer rdb.Close()
 
-			// consistency after every insert
-			for _, ipRange := range tt.ipRanges {
+				// consistency after every insert
+				for _, ipRange := range tt.ipRanges {
 
-				if err := rdb.Insert(context.TODO(), ipRange.Range, ipRange.Reason); (err != nil) != tt.wantErr {
-					t.Errorf("rdb.Insert() error = %v, wantErr %v, range passed: %q", err, tt.wantErr, ipRange.Range)
-					return
-				}
+					if err := rdb.Insert(context.TODO(), ipRange.Range, ipRange.Reason); (err != nil) != tt.wantErr {
+						t.Errorf("rdb.Insert() error = %v, wantErr %v, range passed: %q", err, tt.wantErr, ipRange.Range)
+						return
+					}
 
-				if !consistent(rdb, t, "", idx) {
-					t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", ipRange.Range)
-					return
-				}
-				t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", ipRange.Range)
+					if !consistent(rdb, t, "", idx) {
+						t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", ipRange.Range)
+						return
+					}
+					t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", ipRange.Range)
 
-			}
-		})
+				}
+			})
+		}
 	}
 }
 
@@ -140,41 +146,43 @@ func TestClient_Find(t *testing.T) {
 
 	tests := initTestCasesFind(100)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
+	for _, store := range rangeStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
 
-			rdb := initRDB(0)
-			defer rdb.Close()
+				rdb := store.new()
+				defer rdb.Close()
 
-			for idx, rir := range tt.ipRanges {
-				ipToFind := rir.IP
-				reasonToFind := rir.Reason
-				rangeToFind := rir.Range
+				for idx, rir := range tt.ipRanges {
+					ipToFind := rir.IP
+					reasonToFind := rir.Reason
+					rangeToFind := rir.Range
 
-				err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
-				if err != nil {
-					t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
-					return
-				}
+					err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
+					if err != nil {
+						t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
+						return
+					}
 
-				if !consistent(rdb, t, "", idx) {
-					t.Fatalf("database inconsistent")
-				}
+					if !consistent(rdb, t, "", idx) {
+						t.Fatalf("database inconsistent")
+					}
 
-				got, err := rdb.Find(context.TODO(), ipToFind)
+					got, err := rdb.Find(context.TODO(), ipToFind)
 
-				if (err != nil) != tt.wantErr {
-					t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
-					return
-				}
+					if (err != nil) != tt.wantErr {
+						t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
+						return
+					}
 
-				if got != reasonToFind {
-					t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
-					return
+					if got != reasonToFind {
+						t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
+						return
+					}
 				}
-			}
 
-		})
+			})
+		}
 	}
 }
 
@@ -184,63 +192,65 @@ func TestClient_Remove(t *testing.T) {
 
 	tests = append(tests, initTestCasesFind(100)...)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-
-			rdb := initRDB(0)
-			defer rdb.Close()
-
-			for idx, rir := range tt.ipRanges {
-				ipToFind := rir.IP
-				reasonToFind := rir.Reason
-				rangeToFind := rir.Range
-
-				err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
-				if err != nil {
-					t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
-					t.FailNow()
-				}
-
-				if !consistent(rdb, t, rangeToFind, idx) {
-					t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
-					t.FailNow()
-				}
-				t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
-
-				got, err := rdb.Find(context.TODO(), ipToFind)
-
-				if err != nil {
-					t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
-					return
-				}
-
-				if got != reasonToFind {
-					t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
-					t.FailNow()
-				}
-
-				err = rdb.Remove(context.TODO(), rangeToFind)
-
-				if err != nil {
-					t.Errorf("rdb.Remove(), RETURED ERROR = %q", err)
-					t.FailNow()
-				}
-
-				if !consistent(rdb, t, "", 0) {
-					t.Errorf("rdb.Remove() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
-					t.FailNow()
-				}
-				t.Logf("rdb.Remove() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
-
-				_, err = rdb.Find(context.TODO(), ipToFind)
-
-				// should not be found after range deletion
-				if err == nil {
-					t.Errorf("rdb.Find(),FOUND AFTER RANGE DELETION error = %q\nRange: %q IP: %s", err.Error(), rangeToFind, ipToFind)
-					t.FailNow()
+	for _, store := range rangeStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+
+				rdb := store.new()
+				defer rdb.Close()
+
+				for idx, rir := range tt.ipRanges {
+					ipToFind := rir.IP
+					reasonToFind := rir.Reason
+					rangeToFind := rir.Range
+
+					err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
+					if err != nil {
+						t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
+						t.FailNow()
+					}
+
+					if !consistent(rdb, t, rangeToFind, idx) {
+						t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
+						t.FailNow()
+					}
+					t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
+
+					got, err := rdb.Find(context.TODO(), ipToFind)
+
+					if err != nil {
+						t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
+						return
+					}
+
+					if got != reasonToFind {
+						t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
+						t.FailNow()
+					}
+
+					err = rdb.Remove(context.TODO(), rangeToFind)
+
+					if err != nil {
+						t.Errorf("rdb.Remove(), RETURED ERROR = %q", err)
+						t.FailNow()
+					}
+
+					if !consistent(rdb, t, "", 0) {
+						t.Errorf("rdb.Remove() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
+						t.FailNow()
+					}
+					t.Logf("rdb.Remove() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
+
+					_, err = rdb.Find(context.TODO(), ipToFind)
+
+					// should not be found after range deletion
+					if err == nil {
+						t.Errorf("rdb.Find(),FOUND AFTER RANGE DELETION error = %q\nRange: %q IP: %s", err.Error(), rangeToFind, ipToFind)
+						t.FailNow()
+					}
 				}
-			}
-		})
+			})
+		}
 	}
 }
 
@@ -250,71 +260,73 @@ func TestClient_UpdateReasonOf(t *testing.T) {
 
 	tests = append(tests, initTestCasesFind(100)...)
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-
-			rdb := initRDB(0)
-			defer rdb.Close()
-
-			for idx, rir := range tt.ipRanges {
-				ipToFind := rir.IP
-				reasonToFind := rir.Reason
-				rangeToFind := rir.Range
-
-				err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
-				if err != nil {
-					t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
-					t.FailNow()
-				}
-
-				if !consistent(rdb, t, rangeToFind, idx) {
-					t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
-					t.FailNow()
-				}
-				t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
-
-				got, err := rdb.Find(context.TODO(), ipToFind)
-
-				if err != nil {
-					t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
-					return
-				}
-
-				if got != reasonToFind {
-					t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
-					t.FailNow()
-				}
-
-				newReason := randomString()
-				err = rdb.UpdateReasonOf(context.TODO(), ipToFind, func(s string) string {
-					return newReason
-				})
-
-				if err != nil {
-					t.Errorf("rdb.UpdateReasonOf(), RETURED ERROR = %q", err)
-					t.FailNow()
+	for _, store := range rangeStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+
+				rdb := store.new()
+				defer rdb.Close()
+
+				for idx, rir := range tt.ipRanges {
+					ipToFind := rir.IP
+					reasonToFind := rir.Reason
+					rangeToFind := rir.Range
+
+					err := rdb.Insert(context.TODO(), rangeToFind, reasonToFind)
+					if err != nil {
+						t.Errorf("rdb.Insert() error = %v, wantErr %v", err, tt.wantErr)
+						t.FailNow()
+					}
+
+					if !consistent(rdb, t, rangeToFind, idx) {
+						t.Errorf("rdb.Insert() error : Database INCONSISTENT after inserting range: %s", rangeToFind)
+						t.FailNow()
+					}
+					t.Logf("rdb.Insert() Info  : Database is CONSISTENT after inserting range: %s", rangeToFind)
+
+					got, err := rdb.Find(context.TODO(), ipToFind)
+
+					if err != nil {
+						t.Errorf("rdb.Find(), NOT IN RANGE error = %q, wantErr %v\nRange: %q IP: %s", err.Error(), tt.wantErr, rangeToFind, ipToFind)
+						return
+					}
+
+					if got != reasonToFind {
+						t.Errorf("rdb.Find(), WRONG REASON = %q, want %q", got, reasonToFind)
+						t.FailNow()
+					}
+
+					newReason := randomString()
+					err = rdb.UpdateReasonOf(context.TODO(), ipToFind, func(s string) string {
+						return newReason
+					})
+
+					if err != nil {
+						t.Errorf("rdb.UpdateReasonOf(), RETURED ERROR = %q", err)
+						t.FailNow()
+					}
+
+					if !consistent(rdb, t, "", 0) {
+						t.Errorf("rdb.UpdateReasonOf() error : Database INCONSISTENT after updating range: %s, ip: %s with new reason: %s", rangeToFind, ipToFind, newReason)
+						t.FailNow()
+					}
+					t.Logf("rdb.UpdateReasonOf(): Database CONSISTENT after updating range: %s, ip: %s with new reason: %s", rangeToFind, ipToFind, newReason)
+
+					foundReason, err := rdb.Find(context.TODO(), ipToFind)
+
+					// should be found after update
+					if err != nil {
+						t.Errorf("rdb.Find(): FOUND AFTER RANGE UPDATE error = %q\nRange: %q IP: %s", err.Error(), rangeToFind, ipToFind)
+						t.FailNow()
+					}
+
+					if foundReason != newReason {
+						t.Errorf("rdb.Find(): REASON MISMATCH error = %q\nRange: %q IP: %s, old reason: %q, new reason: %q, found reason: %q", err.Error(), rangeToFind, ipToFind, reasonToFind, newReason, foundReason)
+						t.FailNow()
+					}
 				}
-
-				if !consistent(rdb, t, "", 0) {
-					t.Errorf("rdb.UpdateReasonOf() error : Database INCONSISTENT after updating range: %s, ip: %s with new reason: %s", rangeToFind, ipToFind, newReason)
-					t.FailNow()
-				}
-				t.Logf("rdb.UpdateReasonOf(): Database CONSISTENT after updating range: %s, ip: %s with new reason: %s", rangeToFind, ipToFind, newReason)
-
-				foundReason, err := rdb.Find(context.TODO(), ipToFind)
-
-				// should be found after update
-				if err != nil {
-					t.Errorf("rdb.Find(): FOUND AFTER RANGE UPDATE error = %q\nRange: %q IP: %s", err.Error(), rangeToFind, ipToFind)
-					t.FailNow()
-				}
-
-				if foundReason != newReason {
-					t.Errorf("rdb.Find(): REASON MISMATCH error = %q\nRange: %q IP: %s, old reason: %q, new reason: %q, found reason: %q", err.Error(), rangeToFind, ipToFind, reasonToFind, newReason, foundReason)
-					t.FailNow()
-				}
-			}
-		})
+			})
+		}
 	}
 }
 
@@ -324,10 +336,33 @@ type testCase struct {
 	wantErr  bool
 }
 
+// rangeStores are the factories of the RangeStore implementations that the table tests run against.
+// Every factory returns an empty store.
+var rangeStores = []struct {
+	name string
+	new  func() RangeStore
+}{
+	{"Client", func() RangeStore { return initRDB(0) }},
+	{"MemoryClient", func() RangeStore { return NewMemoryClient() }},
+}
+
+// storedBoundaries returns all boundaries of the store including the ±inf boundaries.
+func storedBoundaries(store RangeStore) ([]boundary, error) {
+	switch s := store.(type) {
+	case *Client:
+		return s.all(context.TODO())
+	case *MemoryClient:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return append([]boundary(nil), s.bnds...), nil
+	}
+	return nil, fmt.Errorf("unsupported store %T", store)
+}
+
 // Tests whether the database is in a cosistent state.
-func consistent(rdb *Client, t *testing.T, ipRange string, iteration int) bool {
+func consistent(rdb RangeStore, t *testing.T, ipRange string, iteration int) bool {
 
-	attributes, err := rdb.all(context.TODO())
+	attributes, err := storedBoundaries(rdb)
 	if err != nil {
 		panic(err)
 	}
@@ -493,6 +528,22 @@ func generateRange() (ipRange string, insideIP string) {
 	return cidrRange, betweenIP.String()
 }
 
+// redisAddr is the address of the in-process miniredis server that is shared by all tests.
+var redisAddr string
+
+func TestMain(m *testing.M) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	redisAddr = mr.Addr()
+
+	code := m.Run()
+
+	mr.Close()
+	os.Exit(code)
+}
+
 func initRDB(db int) *Client {
 	if db > 15 {
 		panic("redis only supports database indices from 0 through 15.")
@@ -500,7 +551,7 @@ func initRDB(db int) *Client {
 
 	// new default client
 	c, err := NewClient(context.TODO(), Options{
-		Addr:     "localhost:6379",
+		Addr:     redisAddr,
 		Password: "",
 		DB:       db,
 	})
@@ -602,3 +653,540 @@ func initTestCasesFind(num int) (testCases []testCaseFind) {
 	}
 	return
 }
+
+func TestConcurrentReadWrite(t *testing.T) {
+	const (
+		goroutines = 10
+		operations = 100
+	)
+
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+
+	var wg sync.WaitGroup
+	wg.Add(2 * goroutines)
+
+	for i := 0; i < goroutines; i++ {
+		go func(writer int) {
+			defer wg.Done()
+
+			for j := 0; j < operations; j++ {
+				ipRange, _ := generateRange()
+				reason := fmt.Sprintf("writer %d insert %d", writer, j)
+
+				if err := rdb.Insert(ctx, ipRange, reason); err != nil {
+					t.Errorf("rdb.Insert() error = %v, range passed: %q", err, ipRange)
+					return
+				}
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < operations; j++ {
+				_, ip := generateRange()
+
+				_, err := rdb.Find(ctx, ip)
+				if err != nil && !errors.Is(err, ErrIPNotFound) {
+					t.Errorf("rdb.Find() error = %v, IP passed: %q", err, ip)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if !consistent(rdb, t, "", 1) {
+		t.Fatalf("database INCONSISTENT after concurrent inserts")
+	}
+}
+
+func TestClient_InsertAtRangeBoundary(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+
+	// the single IPs cut the last IP of the range and are placed directly above of it
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.10", "A"},
+		{"10.0.0.10", "B"},
+		{"10.0.0.11", "C"},
+	}
+	for idx, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+		if !consistent(rdb, t, ir.Range, idx) {
+			t.Fatalf("database INCONSISTENT after inserting range: %s", ir.Range)
+		}
+	}
+
+	for ip, want := range map[string]string{
+		"10.0.0.0":  "A",
+		"10.0.0.9":  "A",
+		"10.0.0.10": "B",
+		"10.0.0.11": "C",
+	} {
+		got, err := rdb.Find(ctx, ip)
+		if err != nil || got != want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", ip, got, err, want)
+		}
+	}
+
+	if _, err := rdb.Find(ctx, "10.0.0.12"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.Find(10.0.0.12) error = %v, want %v", err, ErrIPNotFound)
+	}
+}
+
+func TestClient_InsertSingleIPRepresentation(t *testing.T) {
+	bare := initRDB(0)
+	defer bare.Close()
+
+	cidr := initRDB(1)
+	defer cidr.Close()
+
+	ctx := context.TODO()
+	if err := bare.Insert(ctx, "10.0.0.1", "single"); err != nil {
+		t.Fatalf("bare.Insert() error = %v", err)
+	}
+	if err := cidr.Insert(ctx, "10.0.0.1/32", "single"); err != nil {
+		t.Fatalf("cidr.Insert() error = %v", err)
+	}
+
+	want, err := cidr.all(ctx)
+	if err != nil {
+		t.Fatalf("cidr.all() error = %v", err)
+	}
+	got, err := bare.all(ctx)
+	if err != nil {
+		t.Fatalf("bare.all() error = %v", err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("bare IP is stored as %v, /32 as %v", got, want)
+	}
+}
+
+func TestClient_RenameReason(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "legacy-spam"},
+		{"10.0.1.0/24", "other"},
+		{"10.0.2.5", "legacy-spam"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	updated, err := rdb.RenameReason(ctx, "legacy-spam", "spam-v2")
+	if err != nil || updated != 3 {
+		t.Fatalf("rdb.RenameReason() = %d, %v, want 3", updated, err)
+	}
+
+	for ip, want := range map[string]string{"10.0.0.1": "spam-v2", "10.0.1.1": "other", "10.0.2.5": "spam-v2"} {
+		if reason, err := rdb.Find(ctx, ip); err != nil || reason != want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", ip, reason, err, want)
+		}
+	}
+
+	if _, err := rdb.RenameReason(ctx, "legacy-spam", "spam-v2"); !errors.Is(err, ErrIPNotFound) {
+		t.Errorf("rdb.RenameReason() error = %v, want %v", err, ErrIPNotFound)
+	}
+}
+
+func TestClient_UpdateReasonOfRange(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.0.10 - 10.0.0.20", "second"},
+		{"10.0.1.5", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	suffix := func(reason string) string { return reason + "-updated" }
+
+	for _, r := range []string{"10.0.0.10 - 10.0.0.20", "10.0.1.5"} {
+		if err := rdb.UpdateReasonOfRange(ctx, r, suffix); err != nil {
+			t.Fatalf("rdb.UpdateReasonOfRange(%s) error = %v", r, err)
+		}
+	}
+	for _, r := range []string{"10.0.0.0/24", "10.0.0.11 - 10.0.0.20", "10.0.2.0"} {
+		if err := rdb.UpdateReasonOfRange(ctx, r, suffix); !errors.Is(err, ErrIPNotFound) {
+			t.Fatalf("rdb.UpdateReasonOfRange(%s) error = %v, want %v", r, err, ErrIPNotFound)
+		}
+	}
+
+	tests := map[string]string{
+		"10.0.0.0":  "first",
+		"10.0.0.10": "second-updated",
+		"10.0.0.15": "second-updated",
+		"10.0.0.20": "second-updated",
+		"10.0.0.21": "first",
+		"10.0.1.5":  "third-updated",
+	}
+	for ip, want := range tests {
+		if reason, err := rdb.Find(ctx, ip); err != nil || reason != want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", ip, reason, err, want)
+		}
+	}
+}
+
+func TestClient_InsertOrUpdate(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	upserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.0.10 - 10.0.0.20", "second"},
+		{"10.0.0.10 - 10.0.0.20", "second-updated"},
+		{"10.0.1.5", "third"},
+		{"10.0.1.5", "third-updated"},
+		{"10.0.0.0 - 10.0.0.9", "first-updated"},
+	}
+	for idx, ir := range upserts {
+		if err := rdb.InsertOrUpdate(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.InsertOrUpdate() error = %v", err)
+		}
+		if !consistent(rdb, t, ir.Range, idx) {
+			t.Fatalf("database INCONSISTENT after upserting range: %s", ir.Range)
+		}
+	}
+
+	tests := map[string]string{
+		"10.0.0.0":   "first-updated",
+		"10.0.0.9":   "first-updated",
+		"10.0.0.10":  "second-updated",
+		"10.0.0.20":  "second-updated",
+		"10.0.0.21":  "first",
+		"10.0.0.255": "first",
+		"10.0.1.5":   "third-updated",
+	}
+	for ip, want := range tests {
+		if reason, err := rdb.Find(ctx, ip); err != nil || reason != want {
+			t.Errorf("rdb.Find(%s) = %q, %v, want %q", ip, reason, err, want)
+		}
+	}
+
+	count, err := rdb.CountRanges(ctx)
+	if err != nil || count != 4 {
+		t.Errorf("rdb.CountRanges() = %d, %v, want 4", count, err)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		ipRange  string
+		wantLow  string
+		wantHigh string
+		wantErr  error
+	}{
+		{"10.0.0.1", "10.0.0.1", "10.0.0.1", nil},
+		{"10.0.0.0/24", "10.0.0.0", "10.0.0.255", nil},
+		{"10.0.0.0 - 10.0.0.10", "10.0.0.0", "10.0.0.10", nil},
+		{"10.0.0.0...10.0.0.10", "10.0.0.0", "10.0.0.9", nil},
+		{" 10.0.0.0 ... 10.0.1.0 ", "10.0.0.0", "10.0.0.255", nil},
+		{"10.0.0.0...10.0.0.1", "10.0.0.0", "10.0.0.0", nil},
+		{"10.0.0.0...10.0.0.0", "", "", ErrInvalidRange},
+		{"10.0.0.10...10.0.0.0", "", "", ErrInvalidRange},
+		{"10.0.0.0...10.0.0.256", "", "", ErrInvalidRange},
+		{"10.0.0.0..10.0.0.10", "", "", ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		low, high, err := parseRange(tt.ipRange, "reason")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("parseRange(%q) error = %v, want %v", tt.ipRange, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if low.ID != tt.wantLow || high.ID != tt.wantHigh || !low.LowerBound || !high.UpperBound {
+			t.Errorf("parseRange(%q) = %v, %v, want %s - %s", tt.ipRange, low, high, tt.wantLow, tt.wantHigh)
+		}
+	}
+}
+
+func TestClient_InsertIfNoOverlap(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "first"},
+		{"10.0.0.20 - 10.0.0.29", "second"},
+		{"10.0.0.40", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.InsertIfNoOverlap(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.InsertIfNoOverlap() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ipRange string
+		want    []string
+	}{
+		{"10.0.0.10 - 10.0.0.19", nil},
+		{"10.0.0.5", []string{"10.0.0.0 - 10.0.0.9"}},
+		{"10.0.0.9 - 10.0.0.20", []string{"10.0.0.0 - 10.0.0.9", "10.0.0.10 - 10.0.0.19", "10.0.0.20 - 10.0.0.29"}},
+		{"10.0.0.21 - 10.0.0.28", []string{"10.0.0.20 - 10.0.0.29"}},
+		{"10.0.0.0/24", []string{"10.0.0.0 - 10.0.0.9", "10.0.0.10 - 10.0.0.19", "10.0.0.20 - 10.0.0.29", "10.0.0.40 - 10.0.0.40"}},
+		{"10.0.0.30 - 10.0.0.39", nil},
+	}
+	for _, tt := range tests {
+		err := rdb.InsertIfNoOverlap(ctx, tt.ipRange, "new")
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("rdb.InsertIfNoOverlap(%s) error = %v", tt.ipRange, err)
+			}
+			continue
+		}
+
+		var overlap *OverlapError
+		if !errors.As(err, &overlap) || !errors.Is(err, ErrRangeOverlap) {
+			t.Errorf("rdb.InsertIfNoOverlap(%s) error = %v, want %v", tt.ipRange, err, ErrRangeOverlap)
+			continue
+		}
+		got := make([]string, 0, len(overlap.Ranges))
+		for _, r := range overlap.Ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String())
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.InsertIfNoOverlap(%s) overlaps %v, want %v", tt.ipRange, got, tt.want)
+		}
+	}
+}
+
+func TestClient_ReplaceRange(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	tests := []struct {
+		inserts  []rangeReason
+		oldRange string
+		newRange string
+		want     []string
+		wantErr  error
+	}{
+		{
+			[]rangeReason{{"10.0.0.0/24", "blocked"}},
+			"10.0.0.0/24", "10.0.0.0/23",
+			[]string{"10.0.0.0 - 10.0.1.255 extended"},
+			nil,
+		},
+		{
+			[]rangeReason{{"10.0.0.0/23", "blocked"}},
+			"10.0.0.0/23", "10.0.1.0/24",
+			[]string{"10.0.1.0 - 10.0.1.255 extended"},
+			nil,
+		},
+		{
+			[]rangeReason{{"9.255.255.0 - 10.0.0.0", "neighbour"}, {"10.0.0.1 - 10.0.0.9", "blocked"}, {"10.0.0.20 - 10.0.0.29", "neighbour"}},
+			"10.0.0.1 - 10.0.0.9", "10.0.0.0 - 10.0.0.5",
+			[]string{"9.255.255.0 - 9.255.255.255 neighbour", "10.0.0.0 - 10.0.0.5 extended", "10.0.0.20 - 10.0.0.29 neighbour"},
+			nil,
+		},
+		{
+			[]rangeReason{{"10.0.0.0 - 10.0.0.9", "blocked"}, {"10.0.0.10 - 10.0.0.29", "neighbour"}},
+			"10.0.0.0 - 10.0.0.9", "10.0.0.10",
+			[]string{"10.0.0.10 - 10.0.0.10 extended", "10.0.0.11 - 10.0.0.29 neighbour"},
+			nil,
+		},
+		{
+			[]rangeReason{{"10.0.0.0/24", "blocked"}},
+			"10.0.0.0/24", "10.0.2.0/24",
+			[]string{"10.0.0.0 - 10.0.0.255 blocked"},
+			ErrInvalidRange,
+		},
+	}
+	for _, tt := range tests {
+		if err := rdb.Reset(ctx); err != nil {
+			t.Fatalf("rdb.Reset() error = %v", err)
+		}
+		for _, ir := range tt.inserts {
+			if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+				t.Fatalf("rdb.Insert() error = %v", err)
+			}
+		}
+
+		err := rdb.ReplaceRange(ctx, tt.oldRange, tt.newRange, "extended")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.ReplaceRange(%s, %s) error = %v, want %v", tt.oldRange, tt.newRange, err, tt.wantErr)
+		}
+		if err := rdb.consistent(ctx); err != nil {
+			t.Errorf("rdb.ReplaceRange(%s, %s) left the database inconsistent: %v", tt.oldRange, tt.newRange, err)
+		}
+
+		ranges, err := rdb.All(ctx)
+		if err != nil {
+			t.Fatalf("rdb.All() error = %v", err)
+		}
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.ReplaceRange(%s, %s) stored %v, want %v", tt.oldRange, tt.newRange, got, tt.want)
+		}
+	}
+}
+
+func TestClient_SplitRange(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	tests := []struct {
+		splitAt string
+		want    []string
+		wantErr error
+	}{
+		{"10.0.0.128", []string{"10.0.0.0 - 10.0.0.128 lower", "10.0.0.129 - 10.0.0.255 upper", "10.0.1.0 - 10.0.1.0 neighbour"}, nil},
+		{"10.0.0.0", []string{"10.0.0.0 - 10.0.0.0 lower", "10.0.0.1 - 10.0.0.255 upper", "10.0.1.0 - 10.0.1.0 neighbour"}, nil},
+		{"10.0.0.254", []string{"10.0.0.0 - 10.0.0.254 lower", "10.0.0.255 - 10.0.0.255 upper", "10.0.1.0 - 10.0.1.0 neighbour"}, nil},
+		{"10.0.0.255", []string{"10.0.0.0 - 10.0.0.255 blocked", "10.0.1.0 - 10.0.1.0 neighbour"}, ErrInvalidRange},
+		{"10.0.1.0", []string{"10.0.0.0 - 10.0.0.255 blocked", "10.0.1.0 - 10.0.1.0 neighbour"}, ErrInvalidRange},
+		{"10.0.2.0", []string{"10.0.0.0 - 10.0.0.255 blocked", "10.0.1.0 - 10.0.1.0 neighbour"}, ErrIPNotFound},
+	}
+	for _, tt := range tests {
+		if err := rdb.Reset(ctx); err != nil {
+			t.Fatalf("rdb.Reset() error = %v", err)
+		}
+		if err := rdb.Insert(ctx, "10.0.0.0/24", "blocked"); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+		if err := rdb.Insert(ctx, "10.0.1.0", "neighbour"); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+
+		err := rdb.SplitRange(ctx, tt.splitAt, "lower", "upper")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.SplitRange(%s) error = %v, want %v", tt.splitAt, err, tt.wantErr)
+		}
+		if err := rdb.consistent(ctx); err != nil {
+			t.Errorf("rdb.SplitRange(%s) left the database inconsistent: %v", tt.splitAt, err)
+		}
+
+		ranges, err := rdb.All(ctx)
+		if err != nil {
+			t.Fatalf("rdb.All() error = %v", err)
+		}
+		got := make([]string, 0, len(ranges))
+		for _, r := range ranges {
+			got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("rdb.SplitRange(%s) stored %v, want %v", tt.splitAt, got, tt.want)
+		}
+	}
+
+	// equal reasons would leave two adjacent ranges with the same reason
+	if err := rdb.SplitRange(ctx, "10.0.0.128", "same", "same"); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("rdb.SplitRange() with equal reasons error = %v, want %v", err, ErrInvalidRange)
+	}
+	if reason, err := rdb.Find(ctx, "10.0.0.200"); err != nil || reason != "blocked" {
+		t.Errorf("rdb.Find() = %q, %v, want %q", reason, err, "blocked")
+	}
+
+	// both parts are merged with adjacent ranges that have the same reason
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	for _, ir := range []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "lower"},
+		{"10.0.0.10 - 10.0.0.19", "blocked"},
+		{"10.0.0.20 - 10.0.0.29", "upper"},
+	} {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+	if err := rdb.SplitRange(ctx, "10.0.0.14", "lower", "upper"); err != nil {
+		t.Fatalf("rdb.SplitRange() error = %v", err)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Errorf("rdb.SplitRange() left the database inconsistent: %v", err)
+	}
+	ranges, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	got := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+	}
+	want := []string{"10.0.0.0 - 10.0.0.14 lower", "10.0.0.15 - 10.0.0.29 upper"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rdb.SplitRange() stored %v, want %v", got, want)
+	}
+}
+
+func TestClient_MergeAdjacentSameReason(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.9", "blocked"},
+		{"10.0.0.10 - 10.0.0.19", "blocked"},
+		{"10.0.0.21 - 10.0.0.25", "blocked"},
+		{"10.0.0.20", "blocked"},
+		{"10.0.0.26 - 10.0.0.29", "other"},
+		{"10.0.0.31 - 10.0.0.39", "other"},
+		{"10.0.0.30", "other"},
+		{"10.0.1.0", "single"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	merges, err := rdb.MergeAdjacentSameReason(ctx)
+	if err != nil || merges != 5 {
+		t.Fatalf("rdb.MergeAdjacentSameReason() = %d, %v, want 5", merges, err)
+	}
+	if err := rdb.consistent(ctx); err != nil {
+		t.Fatalf("rdb.MergeAdjacentSameReason() left the database inconsistent: %v", err)
+	}
+
+	ranges, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	got := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		got = append(got, r.Low.String()+" - "+r.High.String()+" "+r.Reason)
+	}
+	want := []string{
+		"10.0.0.0 - 10.0.0.25 blocked",
+		"10.0.0.26 - 10.0.0.39 other",
+		"10.0.1.0 - 10.0.1.0 single",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rdb.All() = %v, want %v", got, want)
+	}
+
+	merges, err = rdb.MergeAdjacentSameReason(ctx)
+	if err != nil || merges != 0 {
+		t.Errorf("rdb.MergeAdjacentSameReason() = %d, %v, want 0", merges, err)
+	}
+}
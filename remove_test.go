@@ -0,0 +1,48 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClient_RemoveMiddle(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0 - 10.0.0.10", "reason"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	if err := rdb.Remove(ctx, "10.0.0.3 - 10.0.0.5"); err != nil {
+		t.Fatalf("rdb.Remove() error = %v", err)
+	}
+
+	// the part above the removed range must remain a range on its own
+	if !consistent(rdb, t, "10.0.0.6 - 10.0.0.10", 1) {
+		t.Fatalf("database inconsistent after removing the middle of a range")
+	}
+
+	tests := []struct {
+		ip         string
+		wantReason string
+		wantErr    error
+	}{
+		{"10.0.0.0", "reason", nil},
+		{"10.0.0.2", "reason", nil},
+		{"10.0.0.3", "", ErrIPNotFound},
+		{"10.0.0.5", "", ErrIPNotFound},
+		{"10.0.0.6", "reason", nil},
+		{"10.0.0.10", "reason", nil},
+	}
+	for _, tt := range tests {
+		reason, err := rdb.Find(ctx, tt.ip)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.Find(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+		}
+		if reason != tt.wantReason {
+			t.Errorf("rdb.Find(%s) = %q, want %q", tt.ip, reason, tt.wantReason)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// RemoveIP removes the IP range from low to high from the database.
+// In contrast to Remove the boundaries are constructed directly from the passed IPs
+// without any string formatting and parsing.
+func (c *Client) RemoveIP(ctx context.Context, low, high net.IP) error {
+	low4, high4 := low.To4(), high.To4()
+	if low4 == nil || high4 == nil {
+		return ErrIPv6NotSupported
+	}
+
+	lowBnd := newBoundary(low4, "", true, false)
+	highBnd := newBoundary(high4, "", false, true)
+	if lowBnd.Cmp(highBnd) > 0 {
+		return fmt.Errorf("%w : %s is greater than %s", ErrInvalidRange, low, high)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.inBounds(lowBnd) || !c.inBounds(highBnd) {
+		return fmt.Errorf("%w : range exceeds the global boundaries", ErrInvalidRange)
+	}
+
+	return c.remove(ctx, lowBnd, highBnd)
+}
@@ -0,0 +1,48 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestClient_RemoveIP(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	if err := rdb.Insert(ctx, "10.0.0.0 - 10.0.0.10", "reason"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	if err := rdb.RemoveIP(ctx, net.ParseIP("10.0.0.3"), net.ParseIP("10.0.0.5")); err != nil {
+		t.Fatalf("rdb.RemoveIP() error = %v", err)
+	}
+
+	tests := []struct {
+		ip      string
+		wantErr error
+	}{
+		{"10.0.0.2", nil},
+		{"10.0.0.3", ErrIPNotFound},
+		{"10.0.0.5", ErrIPNotFound},
+		{"10.0.0.6", nil},
+		{"10.0.0.8", nil},
+		{"10.0.0.10", nil},
+	}
+	for _, tt := range tests {
+		if _, err := rdb.Find(ctx, tt.ip); !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.Find(%s) error = %v, want %v", tt.ip, err, tt.wantErr)
+		}
+	}
+
+	err := rdb.RemoveIP(ctx, net.ParseIP("10.0.0.5"), net.ParseIP("10.0.0.3"))
+	if !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("rdb.RemoveIP() error = %v, want %v", err, ErrInvalidRange)
+	}
+	err = rdb.RemoveIP(ctx, net.ParseIP("::1"), net.ParseIP("::2"))
+	if !errors.Is(err, ErrIPv6NotSupported) {
+		t.Errorf("rdb.RemoveIP() error = %v, want %v", err, ErrIPv6NotSupported)
+	}
+}
@@ -0,0 +1,90 @@
+package goripr
+
+import (
+	"context"
+)
+
+// ReadOnlyClient only exposes the methods of a Client that do not modify the database.
+// It is supposed to be used with a redis ACL user that only has read permissions.
+// The lookups read the vicinity of an IP within MULTI/EXEC transactions, which is why the user
+// needs the transaction commands as well, e.g.
+//
+//	ACL SETUSER reader on >password ~* +@read +@transaction +@connection
+type ReadOnlyClient struct {
+	c *Client
+}
+
+// NewReadOnlyClient creates a new client that can only read from the database.
+// In contrast to NewClient the global ±inf boundaries are not initialized, as that requires write permissions.
+// The database must have been initialized by a Client or WriteOnlyClient beforehand.
+func NewReadOnlyClient(ctx context.Context, opts Options, options ...Option) (*ReadOnlyClient, error) {
+	c, err := newClient(ctx, opts, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadOnlyClient{c: c}, nil
+}
+
+// Close the redis database connection
+func (r *ReadOnlyClient) Close() error {
+	return r.c.Close()
+}
+
+// Find searches for the requested IP in the database, see Client.Find
+func (r *ReadOnlyClient) Find(ctx context.Context, ip string) (reason string, err error) {
+	return r.c.Find(ctx, ip)
+}
+
+// FindBatch searches for all of the passed IPs in the database, see Client.FindBatch
+func (r *ReadOnlyClient) FindBatch(ctx context.Context, ips []string) ([]FindResult, error) {
+	return r.c.FindBatch(ctx, ips)
+}
+
+// ListAll returns all of the stored ranges in ascending order, see Client.All
+func (r *ReadOnlyClient) ListAll(ctx context.Context) ([]RangeInfo, error) {
+	return r.c.All(ctx)
+}
+
+// Statistics returns usage information of the database, see Client.Stats
+func (r *ReadOnlyClient) Statistics(ctx context.Context) (*Stats, error) {
+	return r.c.Stats(ctx)
+}
+
+// WriteOnlyClient only exposes the methods of a Client that modify the database.
+// Inserting and removing ranges requires the neighbouring boundaries to be read,
+// which is why the redis ACL user still needs read permissions on the goripr keys, e.g.
+//
+//	ACL SETUSER writer on >password ~* +@read +@write +@transaction +@connection +flushdb
+type WriteOnlyClient struct {
+	c *Client
+}
+
+// NewWriteOnlyClient creates a new client that can only modify the database.
+// Like NewClient it initializes the global ±inf boundaries.
+func NewWriteOnlyClient(ctx context.Context, opts Options, options ...Option) (*WriteOnlyClient, error) {
+	c, err := NewClient(ctx, opts, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &WriteOnlyClient{c: c}, nil
+}
+
+// Close the redis database connection
+func (w *WriteOnlyClient) Close() error {
+	return w.c.Close()
+}
+
+// Insert inserts a new IP range or IP into the database, see Client.Insert
+func (w *WriteOnlyClient) Insert(ctx context.Context, ipRange, reason string) error {
+	return w.c.Insert(ctx, ipRange, reason)
+}
+
+// Remove removes an IP range from the database, see Client.Remove
+func (w *WriteOnlyClient) Remove(ctx context.Context, ipRange string) error {
+	return w.c.Remove(ctx, ipRange)
+}
+
+// Reset the database except for its global boundaries, see Client.Reset
+func (w *WriteOnlyClient) Reset(ctx context.Context) error {
+	return w.c.Reset(ctx)
+}
@@ -0,0 +1,61 @@
+package goripr
+
+import (
+	"context"
+	"testing"
+)
+
+func TestReadOnlyAndWriteOnlyClient(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	if err := rdb.Flush(ctx); err != nil {
+		t.Fatalf("rdb.Flush() error = %v", err)
+	}
+
+	reader, err := NewReadOnlyClient(ctx, Options{Addr: redisAddr})
+	if err != nil {
+		t.Fatalf("NewReadOnlyClient() error = %v", err)
+	}
+	defer reader.Close()
+
+	// must not have written the ±inf boundaries
+	if n := rdb.rdb.ZCard(ctx, IPRangesKey).Val(); n != 0 {
+		t.Fatalf("NewReadOnlyClient() initialized the database: %d boundaries", n)
+	}
+
+	writer, err := NewWriteOnlyClient(ctx, Options{Addr: redisAddr})
+	if err != nil {
+		t.Fatalf("NewWriteOnlyClient() error = %v", err)
+	}
+	defer writer.Close()
+
+	if err := writer.Insert(ctx, "10.0.0.0/24", "writer"); err != nil {
+		t.Fatalf("writer.Insert() error = %v", err)
+	}
+
+	reason, err := reader.Find(ctx, "10.0.0.1")
+	if err != nil || reason != "writer" {
+		t.Fatalf("reader.Find() = %q, %v, want %q", reason, err, "writer")
+	}
+
+	ranges, err := reader.ListAll(ctx)
+	if err != nil || len(ranges) != 1 || ranges[0].Reason != "writer" {
+		t.Fatalf("reader.ListAll() = %v, %v, want the range of the writer", ranges, err)
+	}
+
+	stats, err := reader.Statistics(ctx)
+	if err != nil || stats.RangeCount != 1 || stats.CoveredIPCount != 256 {
+		t.Fatalf("reader.Statistics() = %+v, %v, want a single range of 256 IPs", stats, err)
+	}
+
+	if err := writer.Remove(ctx, "10.0.0.0/24"); err != nil {
+		t.Fatalf("writer.Remove() error = %v", err)
+	}
+
+	if _, err := reader.Find(ctx, "10.0.0.1"); err == nil {
+		t.Fatalf("reader.Find() found removed range")
+	}
+}
@@ -0,0 +1,273 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Scan returns the ranges of up to about count boundaries per call, following the convention of the redis SCAN
+// command: the first call passes the cursor 0 and the scan is complete as soon as the returned cursor is 0 again.
+// It is backed by ZSCAN on IPRangesKey, which is why the ranges are returned in no particular order, count is
+// only a hint and ranges may be returned more than once if the database is modified during the scan.
+// A ZSCAN call may return the lower boundary of a range without its upper boundary, which is why every range is
+// returned by the call that returns its lower boundary, together with its looked up upper boundary.
+// Upper boundaries that are returned by ZSCAN are skipped, as their ranges are returned with their lower boundaries.
+func (c *Client) Scan(ctx context.Context, cursor uint64, count int64) (entries []RangeEntry, nextCursor uint64, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.checkKey(); err != nil {
+		return nil, 0, err
+	}
+
+	members, nextCursor, err := c.rdb.ZScan(ctx, IPRangesKey, cursor, "", count).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	// ZSCAN returns the members and their scores alternately
+	bnds := make([]boundary, 0, len(members)/2)
+	for idx := 0; idx+1 < len(members); idx += 2 {
+		score, err := strconv.ParseFloat(members[idx+1], 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+
+		bnd := c.boundaryOf(redis.Z{Member: members[idx], Score: score})
+		if bnd.IsInfBound() {
+			continue
+		}
+		bnds = append(bnds, bnd)
+	}
+
+	err = c.fetchAttributes(ctx, bnds)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// the nearest boundary above a lower boundary is its upper boundary
+	upperCmds := make([]*redis.ZSliceCmd, len(bnds))
+	tx := c.rdb.TxPipeline()
+	queued := 0
+	for idx := range bnds {
+		if !bnds[idx].LowerBound || bnds[idx].UpperBound {
+			continue
+		}
+		upperCmds[idx] = tx.ZRangeByScoreWithScores(ctx, IPRangesKey, &redis.ZRangeBy{
+			Min:   bnds[idx].Above().Int64String(),
+			Max:   "+inf",
+			Count: 1,
+		})
+		queued++
+	}
+	if queued > 0 {
+		_, err = tx.Exec(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+	}
+
+	entries = make([]RangeEntry, 0, len(bnds))
+	for idx, bnd := range bnds {
+		if !bnd.LowerBound {
+			continue
+		}
+
+		high := bnd
+		if !bnd.UpperBound {
+			above, err := upperCmds[idx].Result()
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+			}
+			if len(above) == 0 {
+				return nil, 0, fmt.Errorf("%w : no upper boundary above %s", ErrDatabaseInconsistent, bnd.ID)
+			}
+			high = c.boundaryOf(above[0])
+		}
+
+		entries = append(entries, RangeEntry{
+			Range:  fmt.Sprintf("%s - %s", bnd.IP, high.IP),
+			Reason: bnd.Reason,
+		})
+	}
+	return entries, nextCursor, nil
+}
+
+// Scanner iterates over all stored ranges page by page.
+// The database is not locked between two pages, meaning that changes made in between
+// may or may not be reflected in the following pages.
+type Scanner struct {
+	c        *Client
+	ctx      context.Context
+	pageSize int64
+
+	min     string
+	pending *boundary
+	done    bool
+	// cursor is the integer representation of the first IP that has not been scanned yet
+	cursor uint64
+
+	ranges []RangeInfo
+	err    error
+}
+
+// Scanner creates a new pull based iterator that fetches up to pageSize boundaries per page.
+// A pageSize < 1 fetches all of the boundaries at once.
+// ctx bounds the whole scan: Next stops with the error of ctx as soon as it is done,
+// even if the context that is passed to Next is not.
+//
+//	s := c.Scanner(ctx, 1000)
+//	for s.Next(ctx) {
+//		for _, r := range s.RangeInfos() {
+//			...
+//		}
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+func (c *Client) Scanner(ctx context.Context, pageSize int64) *Scanner {
+	return &Scanner{
+		c:        c,
+		ctx:      ctx,
+		pageSize: pageSize,
+		min:      "-inf",
+	}
+}
+
+// ScannerAt creates a Scanner like Scanner that starts at the passed cursor of Scanner.Cursor
+// instead of the first range, which resumes an interrupted scan. The cursor 0 starts at the first range.
+func (c *Client) ScannerAt(ctx context.Context, pageSize int64, cursor uint64) *Scanner {
+	s := c.Scanner(ctx, pageSize)
+	if cursor > 0 {
+		s.min = strconv.FormatUint(cursor, 10)
+		s.cursor = cursor
+	}
+	return s
+}
+
+// Next advances to the next page of ranges.
+// It returns false when there are no more ranges left or an error occurred.
+func (s *Scanner) Next(ctx context.Context) bool {
+	s.ranges = nil
+
+	for !s.done && len(s.ranges) == 0 {
+		if err := s.ctx.Err(); err != nil {
+			s.err = err
+			s.done = true
+			return false
+		}
+
+		bnds, err := s.page(ctx)
+		if err != nil {
+			s.err = err
+			s.done = true
+			return false
+		}
+
+		s.ranges, s.pending = pairRanges(s.pending, bnds)
+	}
+
+	if len(s.ranges) == 0 {
+		return false
+	}
+	s.cursor = uint64(ipToInt64(s.ranges[len(s.ranges)-1].High)) + 1
+	return true
+}
+
+// page fetches the next page of boundaries.
+func (s *Scanner) page(ctx context.Context) ([]boundary, error) {
+	s.c.mu.RLock()
+	defer s.c.mu.RUnlock()
+
+	bnds, err := s.c.boundariesFrom(ctx, s.min, s.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.pageSize < 1 || int64(len(bnds)) < s.pageSize {
+		s.done = true
+		return bnds, nil
+	}
+
+	last := bnds[len(bnds)-1]
+	switch {
+	case last.ID == posInfBoundary.ID:
+		s.done = true
+	case math.IsInf(last.Float64, -1):
+		// "(-inf" is not excluding -inf with every redis implementation
+		s.min = "(" + strconv.FormatInt(math.MinInt64, 10)
+	default:
+		s.min = "(" + strconv.FormatFloat(last.Float64, 'f', -1, 64)
+	}
+	return bnds, nil
+}
+
+// RangeInfos returns the ranges of the current page.
+func (s *Scanner) RangeInfos() []RangeInfo {
+	return s.ranges
+}
+
+// Cursor returns the cursor that resumes the scan with ScannerAt after the ranges that were returned so far.
+// It is the integer representation of the first IP that has not been scanned yet.
+func (s *Scanner) Cursor() uint64 {
+	return s.cursor
+}
+
+// Err returns the first error that occurred while scanning.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// rangeIterPageSize is the number of boundaries that RangeIter fetches per page.
+const rangeIterPageSize = 256
+
+// RangeIterResult is a single result of RangeIter. Either Range is set or Err is not nil.
+type RangeIterResult struct {
+	Range RangeInfo
+	Err   error
+}
+
+// RangeIter returns a channel that streams all stored ranges in ascending order.
+// The ranges are fetched page by page with a Scanner while they are consumed.
+// The channel is closed after the last range, after an error was sent or as soon as ctx is cancelled,
+// which is why callers that stop reading early must cancel ctx in order to stop the underlying goroutine.
+//
+//	for result := range c.RangeIter(ctx) {
+//		if result.Err != nil {
+//			...
+//		}
+//		...
+//	}
+func (c *Client) RangeIter(ctx context.Context) <-chan RangeIterResult {
+	results := make(chan RangeIterResult)
+
+	go func() {
+		defer close(results)
+
+		send := func(result RangeIterResult) bool {
+			select {
+			case results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		s := c.Scanner(ctx, rangeIterPageSize)
+		for s.Next(ctx) {
+			for _, r := range s.RangeInfos() {
+				if !send(RangeIterResult{Range: r}) {
+					return
+				}
+			}
+		}
+		if err := s.Err(); err != nil {
+			send(RangeIterResult{Err: err})
+		}
+	}()
+	return results
+}
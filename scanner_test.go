@@ -0,0 +1,191 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func TestClient_Scanner(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+		{"10.0.0.128/25", "fourth"},
+		{"10.0.3.0 - 10.0.3.1", "fifth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	want := []string{
+		"10.0.0.0 - 10.0.0.127 first",
+		"10.0.0.128 - 10.0.0.255 fourth",
+		"10.0.1.5 - 10.0.1.5 second",
+		"10.0.2.0 - 10.0.2.10 third",
+		"10.0.3.0 - 10.0.3.1 fifth",
+	}
+
+	for _, pageSize := range []int64{0, 1, 2, 3, 100} {
+		t.Run(fmt.Sprintf("page size %d", pageSize), func(t *testing.T) {
+			got := make([]string, 0, len(want))
+
+			s := rdb.Scanner(ctx, pageSize)
+			for s.Next(ctx) {
+				for _, r := range s.RangeInfos() {
+					got = append(got, fmt.Sprintf("%s - %s %s", r.Low, r.High, r.Reason))
+				}
+			}
+			if err := s.Err(); err != nil {
+				t.Fatalf("Scanner.Err() = %v", err)
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("Scanner returned %d ranges, want %d: %v", len(got), len(want), got)
+			}
+			for idx := range want {
+				if got[idx] != want[idx] {
+					t.Errorf("Scanner range %d = %q, want %q", idx, got[idx], want[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ScannerCanceled(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	if err := rdb.InsertBatch(context.TODO(), []RangeEntry{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.0/24", "second"},
+	}); err != nil {
+		t.Fatalf("rdb.InsertBatch() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := rdb.Scanner(ctx, 2)
+	if !s.Next(context.TODO()) {
+		t.Fatalf("Scanner.Next() = false, want true, error = %v", s.Err())
+	}
+
+	// the context of the scanner bounds the whole scan
+	cancel()
+	if s.Next(context.TODO()) {
+		t.Errorf("Scanner.Next() = true after the cancelation, want false")
+	}
+	if err := s.Err(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Scanner.Err() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestClient_Scan(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"0.0.0.0", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+		{"10.0.3.0 - 10.0.3.1", "fourth"},
+	}
+	for idx := 0; idx < 200; idx++ {
+		inserts = append(inserts, rangeReason{fmt.Sprintf("11.0.%d.0/24", idx), fmt.Sprintf("subnet %d", idx)})
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	ranges, _ := rdb.All(ctx)
+	want := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		want = append(want, fmt.Sprintf("%s - %s %s", r.Low, r.High, r.Reason))
+	}
+	sort.Strings(want)
+
+	for _, count := range []int64{0, 1, 10, 100, 1000} {
+		got := make([]string, 0, len(want))
+
+		cursor := uint64(0)
+		for calls := 0; ; calls++ {
+			if calls > 2*len(inserts)+2 {
+				t.Fatalf("rdb.Scan() with count %d does not terminate", count)
+			}
+
+			entries, next, err := rdb.Scan(ctx, cursor, count)
+			if err != nil {
+				t.Fatalf("rdb.Scan() error = %v", err)
+			}
+			for _, e := range entries {
+				got = append(got, fmt.Sprintf("%s %s", e.Range, e.Reason))
+			}
+
+			cursor = next
+			if cursor == 0 {
+				break
+			}
+		}
+
+		// ZSCAN does not return the ranges in order
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("rdb.Scan() with count %d = %v, want %v", count, got, want)
+		}
+	}
+}
+
+func TestClient_RangeIter(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	// every second entry overwrites the previous entry with a larger range
+	entries := make([]RangeEntry, 0, 300)
+	for idx := 0; idx < 300; idx++ {
+		high := 10
+		if idx%2 == 1 {
+			high = 100
+		}
+		entries = append(entries, RangeEntry{
+			Range:  fmt.Sprintf("10.0.%d.0 - 10.0.%d.%d", idx/2, idx/2, high),
+			Reason: fmt.Sprintf("reason %d", idx),
+		})
+	}
+	if err := rdb.InsertBatch(ctx, entries); err != nil {
+		t.Fatalf("rdb.InsertBatch() error = %v", err)
+	}
+
+	count := 0
+	for result := range rdb.RangeIter(ctx) {
+		if result.Err != nil {
+			t.Fatalf("rdb.RangeIter() error = %v", result.Err)
+		}
+		want := fmt.Sprintf("10.0.%d.0 - 10.0.%d.100 reason %d", count, count, 2*count+1)
+		if got := fmt.Sprintf("%s - %s %s", result.Range.Low, result.Range.High, result.Range.Reason); got != want {
+			t.Errorf("rdb.RangeIter() range %d = %q, want %q", count, got, want)
+		}
+		count++
+	}
+	if count != 150 {
+		t.Errorf("rdb.RangeIter() returned %d ranges, want 150", count)
+	}
+
+	// stopping early does not block the goroutine
+	cancelCtx, cancel := context.WithCancel(ctx)
+	results := rdb.RangeIter(cancelCtx)
+	<-results
+	cancel()
+	for range results {
+	}
+}
@@ -0,0 +1,60 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xgfone/go-netaddr"
+)
+
+// SoftFind works like Find, but in case the IP is not contained in any range, the reason of the nearest
+// range is returned if that range is at most maxDistance IPs away from the IP.
+// The returned distance is 0 if the IP is contained in a range. In case the nearest ranges below and above
+// have the same distance, the reason of the range below is returned.
+// ErrIPNotFound is returned if there is no range within maxDistance.
+func (c *Client) SoftFind(ctx context.Context, ip string, maxDistance uint32) (reason string, distance uint32, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	reason, err = c.find(ctx, ip)
+	if !errors.Is(err, ErrIPNotFound) {
+		return reason, 0, err
+	}
+
+	ipaddr, err := netaddr.NewIPAddress(ip, 4)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w : %v", ErrInvalidIP, err)
+	}
+	bnd := newBoundary(ipaddr.IP(), "", true, true)
+
+	if !c.inBounds(bnd) {
+		return "", 0, ErrIPNotFound
+	}
+
+	below, _, above, err := c.vicinity(ctx, bnd, bnd, 1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	found := false
+	if len(below) > 0 && !below[0].IsInfBound() {
+		found = true
+		reason = below[0].Reason
+		distance = uint32(bnd.Int64 - below[0].Int64)
+	}
+
+	if len(above) > 0 && !above[0].IsInfBound() {
+		aboveDistance := uint32(above[0].Int64 - bnd.Int64)
+		if !found || aboveDistance < distance {
+			found = true
+			reason = above[0].Reason
+			distance = aboveDistance
+		}
+	}
+
+	if !found || distance > maxDistance {
+		return "", 0, ErrIPNotFound
+	}
+	return reason, distance, nil
+}
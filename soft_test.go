@@ -0,0 +1,50 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestClient_SoftFind(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.10", "low"},
+		{"10.0.0.20 - 10.0.0.30", "high"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		ip           string
+		maxDistance  uint32
+		wantReason   string
+		wantDistance uint32
+		wantErr      error
+	}{
+		{"10.0.0.5", 0, "low", 0, nil},
+		{"10.0.0.12", 5, "low", 2, nil},
+		{"10.0.0.18", 5, "high", 2, nil},
+		{"10.0.0.15", 5, "low", 5, nil},
+		{"10.0.0.15", 4, "", 0, ErrIPNotFound},
+		{"10.0.0.40", 10, "high", 10, nil},
+		{"11.0.0.0", 10, "", 0, ErrIPNotFound},
+		{"invalid", 10, "", 0, ErrInvalidIP},
+	}
+	for _, tt := range tests {
+		reason, distance, err := rdb.SoftFind(ctx, tt.ip, tt.maxDistance)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("rdb.SoftFind(%s, %d) error = %v, want %v", tt.ip, tt.maxDistance, err, tt.wantErr)
+			continue
+		}
+		if reason != tt.wantReason || distance != tt.wantDistance {
+			t.Errorf("rdb.SoftFind(%s, %d) = %q, %d, want %q, %d", tt.ip, tt.maxDistance, reason, distance, tt.wantReason, tt.wantDistance)
+		}
+	}
+}
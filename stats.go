@@ -0,0 +1,217 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"net"
+	"sort"
+)
+
+// HistogramBucket counts all values that are less than or equal to UpperBound
+// and greater than the UpperBound of the previous bucket.
+type HistogramBucket struct {
+	UpperBound uint64
+	Count      uint64
+}
+
+// ReasonSizeDistribution returns the distribution of the byte lengths of all reasons that are stored
+// in the boundaries of the database. The buckets grow exponentially from 1 up to 4096 bytes.
+// A last bucket with an UpperBound of math.MaxUint64 counts all reasons that are even longer.
+// The ±inf boundaries are not taken into account.
+func (c *Client) ReasonSizeDistribution(ctx context.Context) ([]HistogramBucket, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]HistogramBucket, 0, 14)
+	for upper := uint64(1); upper <= 4096; upper *= 2 {
+		buckets = append(buckets, HistogramBucket{UpperBound: upper})
+	}
+	buckets = append(buckets, HistogramBucket{UpperBound: math.MaxUint64})
+
+	for _, bnd := range bnds {
+		if bnd.IsInfBound() {
+			continue
+		}
+
+		size := uint64(len(bnd.Reason))
+		for idx := range buckets {
+			if size <= buckets[idx].UpperBound {
+				buckets[idx].Count++
+				break
+			}
+		}
+	}
+	return buckets, nil
+}
+
+// CountRanges returns the number of stored ranges.
+// Single IP ranges consist of a single boundary, which is why the ranges are counted by their lower boundaries
+// instead of deriving the number from the cardinality of the sorted set.
+func (c *Client) CountRanges(ctx context.Context) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	count := int64(0)
+	for _, bnd := range bnds {
+		if !bnd.IsInfBound() && bnd.LowerBound {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountCoveredIPs returns the number of IPs that are contained in any of the stored ranges.
+func (c *Client) CountCoveredIPs(ctx context.Context) (int64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := int64(0)
+	for _, r := range ranges {
+		count += ipToInt64(r.High) - ipToInt64(r.Low) + 1
+	}
+	return count, nil
+}
+
+// SingleIPRanges returns the IPs of all ranges that consist of a single IP in ascending order.
+func (c *Client) SingleIPRanges(ctx context.Context) ([]net.IP, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	ips := make([]net.IP, 0)
+	for _, bnd := range bnds {
+		if !bnd.IsInfBound() && bnd.IsDoubleBound() {
+			ips = append(ips, bnd.IP)
+		}
+	}
+	return ips, nil
+}
+
+// CIDRAlignedCount returns the number of stored ranges that can be represented by a single CIDR prefix
+// as well as the total number of stored ranges.
+func (c *Client) CIDRAlignedCount(ctx context.Context) (aligned, total int, err error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, r := range ranges {
+		if isCIDRAligned(uint32(ipToInt64(r.Low)), uint32(ipToInt64(r.High))) {
+			aligned++
+		}
+	}
+	return aligned, len(ranges), nil
+}
+
+// Stats contains usage information of the database.
+type Stats struct {
+	// RangeCount is the number of stored ranges.
+	RangeCount int64
+	// BoundaryCount is the number of members of the sorted set including the ±inf boundaries.
+	BoundaryCount int64
+	// CoveredIPCount is the number of IPs that are contained in any of the stored ranges.
+	CoveredIPCount int64
+	// RedisKeyCount is the number of keys of the whole selected database.
+	RedisKeyCount int64
+	// MemoryUsedBytes is the memory usage of the sorted set as reported by MEMORY USAGE,
+	// without the memory of the boundary attributes.
+	MemoryUsedBytes int64
+}
+
+// Stats returns usage information of the database. The sizes are retrieved within a single pipeline,
+// while the ranges and covered IPs are counted by scanning all boundaries.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	pipe := c.rdb.Pipeline()
+	boundaryCount := pipe.ZCard(ctx, IPRangesKey)
+	keyCount := pipe.DBSize(ctx)
+	memoryUsage := pipe.MemoryUsage(ctx, IPRangesKey)
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+
+	bnds, err := c.all(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	ranges, _ := pairRanges(nil, bnds)
+
+	stats := &Stats{
+		RangeCount:      int64(len(ranges)),
+		BoundaryCount:   boundaryCount.Val(),
+		RedisKeyCount:   keyCount.Val(),
+		MemoryUsedBytes: memoryUsage.Val(),
+	}
+	for _, r := range ranges {
+		stats.CoveredIPCount += ipToInt64(r.High) - ipToInt64(r.Low) + 1
+	}
+	return stats, nil
+}
+
+// RangeSizeInfo is a stored range together with the number of IPs that it covers.
+type RangeSizeInfo struct {
+	Low    net.IP
+	High   net.IP
+	Reason string
+	Size   uint64
+	// SizeCIDREquivalent is the prefix length of the smallest CIDR network that contains at least Size IPs,
+	// e.g. "/24" for any range of 129 up to 256 IPs. It does not imply that the range is aligned to that network.
+	SizeCIDREquivalent string
+}
+
+// RangeSizes returns all stored ranges sorted by the number of IPs that they cover in descending order.
+// Ranges of the same size are sorted in ascending order of their IPs.
+func (c *Client) RangeSizes(ctx context.Context) ([]RangeSizeInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ranges, err := c.listAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]RangeSizeInfo, 0, len(ranges))
+	for _, r := range ranges {
+		size := uint64(ipToInt64(r.High) - ipToInt64(r.Low) + 1)
+		result = append(result, RangeSizeInfo{
+			Low:                r.Low,
+			High:               r.High,
+			Reason:             r.Reason,
+			Size:               size,
+			SizeCIDREquivalent: fmt.Sprintf("/%d", 32-bits.Len64(size-1)),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Size > result[j].Size
+	})
+	return result, nil
+}
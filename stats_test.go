@@ -0,0 +1,209 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestClient_ReasonSizeDistribution(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "abc"},
+		{"10.0.1.1", ""},
+		{"10.0.2.0/30", strings.Repeat("x", 5000)},
+		{"10.0.3.0 - 10.0.3.10", strings.Repeat("x", 4096)},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	buckets, err := rdb.ReasonSizeDistribution(ctx)
+	if err != nil {
+		t.Fatalf("rdb.ReasonSizeDistribution() error = %v", err)
+	}
+
+	want := map[uint64]uint64{
+		1:              1,
+		4:              2,
+		4096:           2,
+		math.MaxUint64: 2,
+	}
+
+	if len(buckets) != 14 {
+		t.Fatalf("got %d buckets, want 14", len(buckets))
+	}
+	for _, b := range buckets {
+		if b.Count != want[b.UpperBound] {
+			t.Errorf("bucket %d: count = %d, want %d", b.UpperBound, b.Count, want[b.UpperBound])
+		}
+	}
+}
+
+func TestClient_CountRanges(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.1", "second"},
+		{"10.0.0.10 - 10.0.0.19", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	ranges, err := rdb.CountRanges(ctx)
+	if err != nil || ranges != 4 {
+		t.Errorf("rdb.CountRanges() = %d, %v, want 4", ranges, err)
+	}
+
+	ips, err := rdb.CountCoveredIPs(ctx)
+	if err != nil || ips != 257 {
+		t.Errorf("rdb.CountCoveredIPs() = %d, %v, want 257", ips, err)
+	}
+}
+
+func TestClient_SingleIPRanges(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "range"},
+		{"10.0.1.1", "single"},
+		{"10.0.0.5", "single"},
+		{"10.0.2.0 - 10.0.2.1", "range"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	got, err := rdb.SingleIPRanges(ctx)
+	if err != nil {
+		t.Fatalf("rdb.SingleIPRanges() error = %v", err)
+	}
+
+	want := []string{"10.0.0.5", "10.0.1.1"}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.SingleIPRanges() = %v, want %v", got, want)
+	}
+	for idx, ip := range got {
+		if ip.String() != want[idx] {
+			t.Errorf("rdb.SingleIPRanges()[%d] = %s, want %s", idx, ip, want[idx])
+		}
+	}
+}
+
+func TestClient_CIDRAlignedCount(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "aligned"},
+		{"10.0.1.5", "aligned single IP"},
+		{"10.0.2.0 - 10.0.2.2", "odd size"},
+		{"10.0.3.2 - 10.0.3.5", "unaligned start"},
+		{"10.0.4.4 - 10.0.4.7", "aligned"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	aligned, total, err := rdb.CIDRAlignedCount(ctx)
+	if err != nil || aligned != 3 || total != 5 {
+		t.Errorf("rdb.CIDRAlignedCount() = %d, %d, %v, want 3, 5", aligned, total, err)
+	}
+}
+
+func TestClient_Stats(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.9", "third"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	stats, err := rdb.Stats(ctx)
+	if err != nil {
+		t.Fatalf("rdb.Stats() error = %v", err)
+	}
+
+	// 5 boundaries and the ±inf boundaries, each with its attribute hash, and the sorted set
+	want := Stats{
+		RangeCount:     3,
+		BoundaryCount:  7,
+		CoveredIPCount: 267,
+		RedisKeyCount:  8,
+	}
+	if stats.MemoryUsedBytes <= 0 {
+		t.Errorf("rdb.Stats() MemoryUsedBytes = %d, want > 0", stats.MemoryUsedBytes)
+	}
+	stats.MemoryUsedBytes = 0
+	if *stats != want {
+		t.Errorf("rdb.Stats() = %+v, want %+v", *stats, want)
+	}
+}
+
+func TestClient_RangeSizes(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.1", "single"},
+		{"10.0.1.0 - 10.0.1.199", "partial"},
+		{"10.0.2.0/24", "network"},
+		{"10.0.4.0 - 10.0.5.0", "large"},
+		{"10.0.6.0/24", "second network"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	sizes, err := rdb.RangeSizes(ctx)
+	if err != nil {
+		t.Fatalf("rdb.RangeSizes() error = %v", err)
+	}
+
+	want := []string{
+		"large 257 /23",
+		"network 256 /24",
+		"second network 256 /24",
+		"partial 200 /24",
+		"single 1 /32",
+	}
+	if len(sizes) != len(want) {
+		t.Fatalf("rdb.RangeSizes() = %v, want %v", sizes, want)
+	}
+	for idx, s := range sizes {
+		if got := fmt.Sprintf("%s %d %s", s.Reason, s.Size, s.SizeCIDREquivalent); got != want[idx] {
+			t.Errorf("rdb.RangeSizes()[%d] = %s, want %s", idx, got, want[idx])
+		}
+	}
+}
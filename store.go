@@ -0,0 +1,22 @@
+package goripr
+
+import "context"
+
+// RangeStore is the set of operations that Client provides to manage the IP ranges.
+// Code that depends on RangeStore instead of *Client can be tested without a redis server,
+// e.g. with the MockRangeStore of the goriprtest package.
+type RangeStore interface {
+	Insert(ctx context.Context, ipRange, reason string) error
+	Remove(ctx context.Context, ipRange string) error
+	Find(ctx context.Context, ip string) (reason string, err error)
+	FindMany(ctx context.Context, ips []string) (map[string]string, error)
+	UpdateReasonOf(ctx context.Context, ip string, fn UpdateFunc) error
+	InsertBatch(ctx context.Context, entries []RangeEntry) error
+	RemoveBatch(ctx context.Context, ranges []string) error
+	All(ctx context.Context) ([]RangeInfo, error)
+	Flush(ctx context.Context) error
+	Reset(ctx context.Context) error
+	Close() error
+}
+
+var _ RangeStore = (*Client)(nil)
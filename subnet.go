@@ -0,0 +1,128 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/xgfone/go-netaddr"
+)
+
+// MaxSubnetIPs is the maximum number of IPs that are returned when listing the IPs of a subnet.
+// Subnets that are larger than a /24 network may exceed this limit, in which case only the first
+// MaxSubnetIPs IPs are returned together with ErrSubnetTruncated.
+const MaxSubnetIPs = 10000
+
+// UncoveredInSubnet returns all IPs of the passed subnet (<IP>/<0-32>) that are not contained in any stored range,
+// meaning that Find would return ErrIPNotFound for each of them.
+// If there are more than MaxSubnetIPs of them, the first MaxSubnetIPs are returned together with ErrSubnetTruncated.
+func (c *Client) UncoveredInSubnet(ctx context.Context, cidr string) ([]net.IP, error) {
+	return c.subnetIPs(ctx, cidr, false)
+}
+
+// CoveredInSubnet returns all IPs of the passed subnet (<IP>/<0-32>) that are contained in any stored range,
+// meaning that Find would return a reason for each of them.
+// If there are more than MaxSubnetIPs of them, the first MaxSubnetIPs are returned together with ErrSubnetTruncated.
+func (c *Client) CoveredInSubnet(ctx context.Context, cidr string) ([]net.IP, error) {
+	return c.subnetIPs(ctx, cidr, true)
+}
+
+// subnetPageSize is the number of boundaries that subnetIPs fetches at once.
+const subnetPageSize = 256
+
+// subnetIPs returns the IPs of the passed subnet whose coverage matches covered.
+// It walks from boundary to boundary and fetches the boundaries page by page, which is why the work depends
+// on the number of stored boundaries within the subnet and the number of returned IPs,
+// but not on the size of the subnet.
+func (c *Client) subnetIPs(ctx context.Context, cidr string, covered bool) ([]net.IP, error) {
+	network, err := netaddr.NewIPNetwork(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %v", ErrInvalidRange, err)
+	}
+
+	if network.First().IP().To4() == nil {
+		return nil, ErrIPv6NotSupported
+	}
+
+	low := newBoundary(network.First().IP(), "", true, false)
+	high := newBoundary(network.Last().IP(), "", false, true)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if err := c.checkKey(); err != nil {
+		return nil, err
+	}
+
+	below, err := c.nearestStored(ctx, math.Inf(-1), low.Float64-1, true, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	// the subnet starts within a range that began below of it,
+	// there is nothing below of custom global boundaries.
+	inRange := len(below) > 0 && below[0].IsLowerBound() && !below[0].IsInfBound()
+
+	result := make([]net.IP, 0)
+	truncated := false
+	// appendRun appends the IPs from first to last if their coverage matches
+	appendRun := func(first, last int64, isCovered bool) {
+		if isCovered != covered {
+			return
+		}
+		for i := first; i <= last; i++ {
+			if len(result) == MaxSubnetIPs {
+				truncated = true
+				return
+			}
+			result = append(result, ipFromInt64(i))
+		}
+	}
+
+	store := c.newRedisStore(ctx, nil)
+	next := low.Int64
+	for next <= high.Int64 && !truncated {
+		bnds, err := store.ZRangeByScore(float64(next), high.Float64, false, subnetPageSize)
+		if err != nil {
+			return nil, err
+		}
+		err = store.GetBoundaryAttrs(bnds)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, bnd := range bnds {
+			if bnd.Expired {
+				// expired boundaries that have not been swept, yet, do not change the coverage
+				continue
+			}
+
+			// IPs between the previous and the current boundary
+			appendRun(next, bnd.Int64-1, inRange)
+
+			// every boundary is part of a range,
+			// but only a single lower boundary covers the following IPs as well
+			isCovered := !bnd.IsInfBound()
+			appendRun(bnd.Int64, bnd.Int64, isCovered)
+			inRange = isCovered && bnd.IsLowerBound()
+			next = bnd.Int64 + 1
+		}
+
+		if len(bnds) < subnetPageSize {
+			// IPs after the last boundary
+			appendRun(next, high.Int64, inRange)
+			break
+		}
+
+		// IPs up to an expired last boundary of the page
+		last := bnds[len(bnds)-1].Int64
+		appendRun(next, last, inRange)
+		next = last + 1
+	}
+
+	if truncated {
+		return result, ErrSubnetTruncated
+	}
+	return result, nil
+}
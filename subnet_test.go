@@ -0,0 +1,145 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestClient_UncoveredInSubnet(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"10.0.0.0 - 10.0.0.2", "first"},
+		{"10.0.0.4", "second"},
+		{"10.0.0.6 - 10.0.0.9", "third"},
+		{"10.0.0.14 - 10.0.1.255", "fourth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	got, err := rdb.UncoveredInSubnet(ctx, "10.0.0.0/28")
+	if err != nil {
+		t.Fatalf("rdb.UncoveredInSubnet() error = %v", err)
+	}
+
+	want := []string{"10.0.0.3", "10.0.0.5", "10.0.0.10", "10.0.0.11", "10.0.0.12", "10.0.0.13"}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.UncoveredInSubnet() = %v, want %v", got, want)
+	}
+	for idx := range want {
+		if got[idx].String() != want[idx] {
+			t.Errorf("rdb.UncoveredInSubnet()[%d] = %s, want %s", idx, got[idx], want[idx])
+		}
+	}
+
+	got, err = rdb.UncoveredInSubnet(ctx, "10.0.1.0/24")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("rdb.UncoveredInSubnet() = %v, %v, want no IPs", got, err)
+	}
+
+	got, err = rdb.UncoveredInSubnet(ctx, "11.0.0.0/8")
+	if !errors.Is(err, ErrSubnetTruncated) || len(got) != MaxSubnetIPs {
+		t.Fatalf("rdb.UncoveredInSubnet() returned %d IPs, %v, want %d, %v", len(got), err, MaxSubnetIPs, ErrSubnetTruncated)
+	}
+
+	// exactly MaxSubnetIPs uncovered IPs are not truncated
+	if err := rdb.Insert(ctx, "12.0.39.16 - 12.0.63.255", "rest"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	got, err = rdb.UncoveredInSubnet(ctx, "12.0.0.0/18")
+	if err != nil || len(got) != MaxSubnetIPs {
+		t.Fatalf("rdb.UncoveredInSubnet() returned %d IPs, %v, want %d", len(got), err, MaxSubnetIPs)
+	}
+}
+
+func TestClient_CoveredInSubnet(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	inserts := []rangeReason{
+		{"9.255.255.0/24", "first"},
+		{"10.0.0.1 - 10.0.0.2", "second"},
+		{"10.0.0.4", "third"},
+		{"10.0.0.7 - 10.0.0.9", "fourth"},
+		{"10.0.0.15 - 10.0.1.255", "fifth"},
+	}
+	for _, ir := range inserts {
+		if err := rdb.Insert(ctx, ir.Range, ir.Reason); err != nil {
+			t.Fatalf("rdb.Insert() error = %v", err)
+		}
+	}
+
+	got, err := rdb.CoveredInSubnet(ctx, "10.0.0.0/28")
+	if err != nil {
+		t.Fatalf("rdb.CoveredInSubnet() error = %v", err)
+	}
+
+	want := []string{"10.0.0.1", "10.0.0.2", "10.0.0.4", "10.0.0.7", "10.0.0.8", "10.0.0.9", "10.0.0.15"}
+	if len(got) != len(want) {
+		t.Fatalf("rdb.CoveredInSubnet() = %v, want %v", got, want)
+	}
+	for idx := range want {
+		if got[idx].String() != want[idx] {
+			t.Errorf("rdb.CoveredInSubnet()[%d] = %s, want %s", idx, got[idx], want[idx])
+		}
+
+		if _, err := rdb.Find(ctx, want[idx]); err != nil {
+			t.Errorf("rdb.Find(%s) error = %v", want[idx], err)
+		}
+	}
+
+	got, err = rdb.CoveredInSubnet(ctx, "10.0.1.0/24")
+	if err != nil || len(got) != 256 {
+		t.Fatalf("rdb.CoveredInSubnet() returned %d IPs, %v, want 256", len(got), err)
+	}
+
+	got, err = rdb.CoveredInSubnet(ctx, "11.0.0.0/24")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("rdb.CoveredInSubnet() = %v, %v, want no IPs", got, err)
+	}
+}
+
+func TestClient_CoveredInSubnet_Large(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	// more single IP ranges than boundaries are fetched at once
+	entries := make([]RangeEntry, 0, 2*subnetPageSize)
+	for i := 0; i < 2*subnetPageSize; i++ {
+		entries = append(entries, RangeEntry{Range: ipFromInt64(ipToInt64(net.ParseIP("10.1.0.0")) + 2*int64(i)).String(), Reason: "single"})
+	}
+	if err := rdb.InsertBatch(ctx, entries); err != nil {
+		t.Fatalf("rdb.InsertBatch() error = %v", err)
+	}
+
+	// must not step through every IP of the whole IPv4 space
+	got, err := rdb.CoveredInSubnet(ctx, "0.0.0.0/0")
+	if err != nil {
+		t.Fatalf("rdb.CoveredInSubnet() error = %v", err)
+	}
+	if len(got) != len(entries) {
+		t.Fatalf("rdb.CoveredInSubnet() returned %d IPs, want %d", len(got), len(entries))
+	}
+	for idx, entry := range entries {
+		if got[idx].String() != entry.Range {
+			t.Errorf("rdb.CoveredInSubnet()[%d] = %s, want %s", idx, got[idx], entry.Range)
+		}
+	}
+
+	got, err = rdb.UncoveredInSubnet(ctx, "10.1.0.0/16")
+	if !errors.Is(err, ErrSubnetTruncated) || len(got) != MaxSubnetIPs {
+		t.Fatalf("rdb.UncoveredInSubnet() returned %d IPs, %v, want %d, %v", len(got), err, MaxSubnetIPs, ErrSubnetTruncated)
+	}
+	if got[0].String() != "10.1.0.1" || got[1].String() != "10.1.0.3" {
+		t.Errorf("rdb.UncoveredInSubnet() = %v..., want 10.1.0.1, 10.1.0.3, ...", got[:2])
+	}
+}
@@ -0,0 +1,100 @@
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RESPError is an error reply of the redis server, e.g. "ERR unknown command".
+type RESPError string
+
+func (e RESPError) Error() string {
+	return string(e)
+}
+
+// FormatAsRESP2 encodes the command and its arguments as a RESP2 array of bulk strings.
+// The result can be sent as is to a redis server, e.g. with
+//
+//	printf '%s' "$cmd" | nc localhost 6379
+func FormatAsRESP2(cmd string, args ...string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args)+1)
+	for _, arg := range append([]string{cmd}, args...) {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return sb.String()
+}
+
+// ParseRESP2Response decodes a single RESP2 reply.
+// Simple strings and bulk strings are returned as string, integers as int64 and arrays as []interface{}.
+// Null bulk strings and null arrays are returned as nil. Error replies are returned as RESPError.
+func ParseRESP2Response(resp string) (interface{}, error) {
+	value, rest, err := parseRESP2(resp)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected data after the reply: %q", rest)
+	}
+	if respErr, ok := value.(RESPError); ok {
+		return nil, respErr
+	}
+	return value, nil
+}
+
+// parseRESP2 decodes the first reply of resp and returns the remaining data.
+func parseRESP2(resp string) (value interface{}, rest string, err error) {
+	line, rest, found := strings.Cut(resp, "\r\n")
+	if !found || line == "" {
+		return nil, "", errors.New("incomplete reply: missing CRLF")
+	}
+
+	prefix, payload := line[0], line[1:]
+	switch prefix {
+	case '+':
+		return payload, rest, nil
+	case '-':
+		return RESPError(payload), rest, nil
+	case ':':
+		i, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid integer reply: %w", err)
+		}
+		return i, rest, nil
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < -1 {
+			return nil, "", fmt.Errorf("invalid bulk string length: %q", payload)
+		}
+		if n == -1 {
+			return nil, rest, nil
+		}
+		if len(rest) < n+2 || rest[n:n+2] != "\r\n" {
+			return nil, "", errors.New("incomplete bulk string")
+		}
+		return rest[:n], rest[n+2:], nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < -1 {
+			return nil, "", fmt.Errorf("invalid array length: %q", payload)
+		}
+		if n == -1 {
+			return nil, rest, nil
+		}
+
+		elements := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			var element interface{}
+			element, rest, err = parseRESP2(rest)
+			if err != nil {
+				return nil, "", fmt.Errorf("array element %d: %w", i, err)
+			}
+			elements = append(elements, element)
+		}
+		return elements, rest, nil
+	default:
+		return nil, "", fmt.Errorf("unknown reply type: %q", prefix)
+	}
+}
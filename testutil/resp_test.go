@@ -0,0 +1,76 @@
+package testutil
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestFormatAsRESP2(t *testing.T) {
+	got := FormatAsRESP2("ZSCORE", "ip:ranges", "10.0.0.1")
+	want := "*3\r\n$6\r\nZSCORE\r\n$9\r\nip:ranges\r\n$8\r\n10.0.0.1\r\n"
+	if got != want {
+		t.Errorf("FormatAsRESP2() = %q, want %q", got, want)
+	}
+}
+
+func TestParseRESP2Response(t *testing.T) {
+	tests := []struct {
+		resp    string
+		want    interface{}
+		wantErr bool
+	}{
+		{"+OK\r\n", "OK", false},
+		{":42\r\n", int64(42), false},
+		{"$5\r\nhello\r\n", "hello", false},
+		{"$0\r\n\r\n", "", false},
+		{"$-1\r\n", nil, false},
+		{"*-1\r\n", nil, false},
+		{"*2\r\n$1\r\na\r\n*1\r\n:1\r\n", []interface{}{"a", []interface{}{int64(1)}}, false},
+		{"-ERR unknown command\r\n", nil, true},
+		{"$5\r\nhel\r\n", nil, true},
+		{"+OK", nil, true},
+		{"+OK\r\n+OK\r\n", nil, true},
+		{"?\r\n", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRESP2Response(tt.resp)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRESP2Response(%q) = %#v, %v, want %#v", tt.resp, got, err, tt.want)
+		}
+	}
+
+	var respErr RESPError
+	if _, err := ParseRESP2Response("-ERR unknown command\r\n"); !errors.As(err, &respErr) || respErr != "ERR unknown command" {
+		t.Errorf("ParseRESP2Response() error = %v, want a RESPError", err)
+	}
+}
+
+func TestRESP2RoundTrip(t *testing.T) {
+	mr := miniredis.RunT(t)
+	conn, err := net.DialTimeout("tcp", mr.Addr(), time.Second)
+	if err != nil {
+		t.Fatalf("net.Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(FormatAsRESP2("SET", "key", "value"))); err != nil {
+		t.Fatalf("conn.Write() error = %v", err)
+	}
+
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("conn.Read() error = %v", err)
+	}
+
+	got, err := ParseRESP2Response(string(buf[:n]))
+	if err != nil || got != "OK" {
+		t.Errorf("ParseRESP2Response() = %v, %v, want OK", got, err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package testutil provides helpers to set up a goripr.Client against an in-process
+// redis server and to assert its contents with one-liners.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jxsl13/goripr/v2"
+)
+
+// TestHelper wraps a Client that is connected to its own in-process redis server.
+type TestHelper struct {
+	T      *testing.T
+	Client *goripr.Client
+}
+
+// New starts an in-process redis server and connects a new Client to it.
+// Both are shut down via t.Cleanup when the test finishes.
+func New(t *testing.T, opts ...goripr.Option) *TestHelper {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	c, err := goripr.NewClient(context.Background(), goripr.Options{Addr: mr.Addr()}, opts...)
+	if err != nil {
+		t.Fatalf("goripr.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	return &TestHelper{
+		T:      t,
+		Client: c,
+	}
+}
+
+// InsertAll inserts all of the passed ranges in the passed order.
+func (h *TestHelper) InsertAll(ranges []goripr.RangeInfo) {
+	h.T.Helper()
+
+	for _, r := range ranges {
+		ipRange := fmt.Sprintf("%s - %s", r.Low, r.High)
+		if err := h.Client.Insert(context.Background(), ipRange, r.Reason); err != nil {
+			h.T.Fatalf("Insert(%s, %q) error = %v", ipRange, r.Reason, err)
+		}
+	}
+}
+
+// AssertFind fails the test if ip is not found with wantReason.
+func (h *TestHelper) AssertFind(ip, wantReason string) {
+	h.T.Helper()
+
+	reason, err := h.Client.Find(context.Background(), ip)
+	if err != nil {
+		h.T.Errorf("Find(%s) error = %v, want %q", ip, err, wantReason)
+	} else if reason != wantReason {
+		h.T.Errorf("Find(%s) = %q, want %q", ip, reason, wantReason)
+	}
+}
+
+// AssertNotFound fails the test if ip is contained in any range.
+func (h *TestHelper) AssertNotFound(ip string) {
+	h.T.Helper()
+
+	reason, err := h.Client.Find(context.Background(), ip)
+	if !errors.Is(err, goripr.ErrIPNotFound) {
+		h.T.Errorf("Find(%s) = %q, %v, want %v", ip, reason, err, goripr.ErrIPNotFound)
+	}
+}
+
+// AssertRangeCount fails the test if the number of stored ranges is not want.
+func (h *TestHelper) AssertRangeCount(want int) {
+	h.T.Helper()
+
+	got, err := h.Client.CountRanges(context.Background())
+	if err != nil {
+		h.T.Errorf("CountRanges() error = %v", err)
+	} else if got != int64(want) {
+		h.T.Errorf("CountRanges() = %d, want %d", got, want)
+	}
+}
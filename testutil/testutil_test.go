@@ -0,0 +1,22 @@
+package testutil
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jxsl13/goripr/v2"
+)
+
+func TestTestHelper(t *testing.T) {
+	h := New(t)
+
+	h.InsertAll([]goripr.RangeInfo{
+		{Low: net.ParseIP("10.0.0.0"), High: net.ParseIP("10.0.0.255"), Reason: "first"},
+		{Low: net.ParseIP("10.0.1.5"), High: net.ParseIP("10.0.1.5"), Reason: "second"},
+	})
+
+	h.AssertFind("10.0.0.1", "first")
+	h.AssertFind("10.0.1.5", "second")
+	h.AssertNotFound("10.0.1.6")
+	h.AssertRangeCount(2)
+}
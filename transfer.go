@@ -0,0 +1,52 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+)
+
+// transferPageSize is the number of boundaries that are read from the source per page.
+const transferPageSize = 1000
+
+// Transfer copies all ranges of src into dst page by page and returns the number of copied ranges.
+// src is not modified. The copy can be interrupted via ctx, in which case the already copied pages
+// remain in dst. The optional progress callbacks are called with the total number of copied ranges
+// after every page.
+func Transfer(ctx context.Context, src, dst *Client, progress ...func(transferred int)) (transferred int, err error) {
+	transferred, _, err = TransferFrom(ctx, src, dst, 0, transferPageSize, progress...)
+	return transferred, err
+}
+
+// TransferFrom copies the ranges of src into dst like Transfer, but starts at the passed cursor of a Scanner and
+// fetches up to pageSize boundaries per page. The returned cursor is the cursor of the first range that has not
+// been copied, which is why an interrupted transfer can be resumed by passing it to the next call.
+func TransferFrom(ctx context.Context, src, dst *Client, cursor uint64, pageSize int64, progress ...func(transferred int)) (transferred int, next uint64, err error) {
+	s := src.ScannerAt(ctx, pageSize, cursor)
+	for s.Next(ctx) {
+		ranges := s.RangeInfos()
+
+		entries := make([]RangeEntry, 0, len(ranges))
+		for _, r := range ranges {
+			entries = append(entries, RangeEntry{
+				Range:  fmt.Sprintf("%s - %s", r.Low, r.High),
+				Reason: r.Reason,
+			})
+		}
+
+		err = dst.InsertBatch(ctx, entries)
+		if err != nil {
+			return transferred, cursor, err
+		}
+		transferred += len(entries)
+		cursor = s.Cursor()
+
+		for _, fn := range progress {
+			fn(transferred)
+		}
+
+		if ctx.Err() != nil {
+			return transferred, cursor, ctx.Err()
+		}
+	}
+	return transferred, cursor, s.Err()
+}
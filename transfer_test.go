@@ -0,0 +1,88 @@
+package goripr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTransfer(t *testing.T) {
+	src := initRDB(0)
+	defer src.Close()
+
+	dst := initRDB(1)
+	defer dst.Close()
+
+	ctx := context.TODO()
+	for _, r := range ranges {
+		if err := src.Insert(ctx, r.Range, r.Reason); err != nil {
+			t.Fatalf("src.Insert() error = %v", err)
+		}
+	}
+
+	want, err := src.listAll(ctx)
+	if err != nil {
+		t.Fatalf("src.listAll() error = %v", err)
+	}
+
+	calls := 0
+	transferred, err := Transfer(ctx, src, dst, func(int) { calls++ })
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if transferred != len(want) || calls == 0 {
+		t.Errorf("Transfer() = %d with %d progress calls, want %d", transferred, calls, len(want))
+	}
+
+	got, err := dst.listAll(ctx)
+	if err != nil {
+		t.Fatalf("dst.listAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dst ranges = %v, want %v", got, want)
+	}
+
+	if after, _ := src.listAll(ctx); !reflect.DeepEqual(after, want) {
+		t.Errorf("src was modified: %v, want %v", after, want)
+	}
+}
+
+func TestTransferFrom(t *testing.T) {
+	src := initRDB(0)
+	defer src.Close()
+
+	dst := initRDB(1)
+	defer dst.Close()
+
+	ctx := context.TODO()
+	if err := src.InsertBatch(ctx, []RangeEntry{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.10", "third"},
+	}); err != nil {
+		t.Fatalf("src.InsertBatch() error = %v", err)
+	}
+	want, _ := src.listAll(ctx)
+
+	// resume after the first page, which only contains the first range
+	s := src.Scanner(ctx, 1)
+	if !s.Next(ctx) || len(s.RangeInfos()) != 1 {
+		t.Fatalf("Scanner.Next() = %v, %v, want the first range", s.RangeInfos(), s.Err())
+	}
+	if cursor, wantCursor := s.Cursor(), uint64(ipToInt64(want[0].High))+1; cursor != wantCursor {
+		t.Fatalf("Scanner.Cursor() = %d, want %d", cursor, wantCursor)
+	}
+
+	transferred, next, err := TransferFrom(ctx, src, dst, s.Cursor(), 1)
+	if err != nil {
+		t.Fatalf("TransferFrom() error = %v", err)
+	}
+	if wantNext := uint64(ipToInt64(want[2].High)) + 1; transferred != 2 || next != wantNext {
+		t.Errorf("TransferFrom() = %d, %d, want 2, %d", transferred, next, wantNext)
+	}
+
+	got, _ := dst.listAll(ctx)
+	if !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("dst ranges = %v, want %v", got, want[1:])
+	}
+}
@@ -0,0 +1,67 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LegacyIPRangesKey is the key name of the sorted set of databases that were written before all keys
+// were hash tagged with {goripr}. The attribute hashes of their boundaries are stored under the bare
+// members of the sorted set, e.g. 10.0.0.1, instead of BoundaryKeyPrefix + member.
+const LegacyIPRangesKey = "________________IP_RANGES________________"
+
+// upgradePageSize is the number of boundary hashes that are renamed per round trip.
+const upgradePageSize = 1000
+
+// UpgradeKeyLayout renames the keys of a database that was written with the legacy key layout to the
+// current key names: the attribute hash of every boundary is renamed to BoundaryKeyPrefix + member and
+// LegacyIPRangesKey is renamed to IPRangesKey, which replaces the empty sorted set that NewClient created.
+// It does nothing if the database does not contain the LegacyIPRangesKey.
+//
+// The hashes are renamed page by page and the sorted set is renamed last, which is why an interrupted upgrade
+// can be resumed by calling UpgradeKeyLayout again. No other client may use the database during the upgrade.
+// Legacy databases were never written to a redis cluster, so the upgrade must run before the database is
+// imported into a cluster, as RENAME cannot move keys between hash slots.
+func (c *Client) UpgradeKeyLayout(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, err := c.rdb.Exists(ctx, LegacyIPRangesKey).Result()
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	if n == 0 {
+		return nil
+	}
+
+	for start := int64(0); ; start += upgradePageSize {
+		members, err := c.rdb.ZRange(ctx, LegacyIPRangesKey, start, start+upgradePageSize-1).Result()
+		if err != nil {
+			return fmt.Errorf("%w : %v", ErrNoResult, err)
+		}
+		if len(members) == 0 {
+			break
+		}
+
+		pipe := c.rdb.Pipeline()
+		for _, member := range members {
+			pipe.Rename(ctx, member, boundaryKey(member))
+		}
+		cmds, _ := pipe.Exec(ctx)
+
+		for _, cmd := range cmds {
+			// hashes that were renamed by an interrupted upgrade or that have expired do not exist anymore
+			err := cmd.Err()
+			if err != nil && !strings.Contains(err.Error(), "no such key") {
+				return fmt.Errorf("%w : %v", ErrNoResult, err)
+			}
+		}
+	}
+
+	err = c.rdb.Rename(ctx, LegacyIPRangesKey, IPRangesKey).Err()
+	if err != nil {
+		return fmt.Errorf("%w : %v", ErrNoResult, err)
+	}
+	return nil
+}
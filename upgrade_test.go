@@ -0,0 +1,68 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestClient_UpgradeKeyLayout(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb := initRDB(2)
+	defer rdb.Close()
+	defer rdb.Reset(ctx)
+
+	if err := rdb.InsertBatch(ctx, []RangeEntry{
+		{"10.0.0.0/24", "first"},
+		{"10.0.1.5", "second"},
+		{"10.0.2.0 - 10.0.2.9", "third"},
+	}); err != nil {
+		t.Fatalf("rdb.InsertBatch() error = %v", err)
+	}
+	want, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+
+	// move the keys back to the legacy key layout
+	members, err := rdb.rdb.ZRange(ctx, IPRangesKey, 0, -1).Result()
+	if err != nil {
+		t.Fatalf("ZRANGE error = %v", err)
+	}
+	for _, member := range members {
+		if err := rdb.rdb.Rename(ctx, boundaryKey(member), member).Err(); err != nil {
+			t.Fatalf("RENAME %s error = %v", member, err)
+		}
+	}
+	if err := rdb.rdb.Rename(ctx, IPRangesKey, LegacyIPRangesKey).Err(); err != nil {
+		t.Fatalf("RENAME %s error = %v", IPRangesKey, err)
+	}
+	// like NewClient does for a legacy database
+	if err := rdb.init(ctx); err != nil {
+		t.Fatalf("rdb.init() error = %v", err)
+	}
+	// a hash that was already renamed by an interrupted upgrade
+	if err := rdb.rdb.Rename(ctx, "10.0.1.5", boundaryKey("10.0.1.5")).Err(); err != nil {
+		t.Fatalf("RENAME error = %v", err)
+	}
+
+	if err := rdb.UpgradeKeyLayout(ctx); err != nil {
+		t.Fatalf("rdb.UpgradeKeyLayout() error = %v", err)
+	}
+	got, err := rdb.All(ctx)
+	if err != nil {
+		t.Fatalf("rdb.All() error = %v", err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("rdb.All() = %v, want %v", got, want)
+	}
+	if n, _ := rdb.rdb.Exists(ctx, LegacyIPRangesKey, "10.0.0.0").Result(); n != 0 {
+		t.Errorf("%d legacy keys exist after the upgrade, want 0", n)
+	}
+
+	// nothing to upgrade anymore
+	if err := rdb.UpgradeKeyLayout(ctx); err != nil {
+		t.Errorf("rdb.UpgradeKeyLayout() error = %v", err)
+	}
+}
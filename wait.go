@@ -0,0 +1,45 @@
+package goripr
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForCondition calls pred every pollInterval until it either returns true or an error, or ctx is done.
+// pred is evaluated once immediately and is called without holding any locks of the client,
+// which allows it to use any of the client's methods, e.g.
+//
+//	err := c.WaitForCondition(ctx, func(c *Client) (bool, error) {
+//		_, err := c.Find(ctx, "1.2.3.4")
+//		if errors.Is(err, ErrIPNotFound) {
+//			return false, nil
+//		}
+//		return err == nil, err
+//	}, 100*time.Millisecond)
+//
+// A pollInterval < 1 defaults to one second.
+// The error of pred or ctx.Err() is returned in case the condition was not satisfied.
+func (c *Client) WaitForCondition(ctx context.Context, pred func(*Client) (bool, error), pollInterval time.Duration) error {
+	if pollInterval < 1 {
+		pollInterval = time.Second
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		ok, err := pred(c)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
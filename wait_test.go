@@ -0,0 +1,45 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClient_WaitForCondition(t *testing.T) {
+	rdb := initRDB(0)
+	defer rdb.Close()
+
+	ctx := context.TODO()
+	blocked := func(c *Client) (bool, error) {
+		_, err := c.Find(ctx, "1.2.3.4")
+		if errors.Is(err, ErrIPNotFound) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = rdb.Insert(ctx, "1.2.3.0/24", "blocked")
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := rdb.WaitForCondition(waitCtx, blocked, 10*time.Millisecond); err != nil {
+		t.Fatalf("rdb.WaitForCondition() error = %v", err)
+	}
+
+	err := rdb.Reset(ctx)
+	if err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+
+	timeoutCtx, cancelTimeout := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelTimeout()
+	err = rdb.WaitForCondition(timeoutCtx, blocked, 10*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("rdb.WaitForCondition() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
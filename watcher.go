@@ -0,0 +1,97 @@
+package goripr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WithKeyWatcher watches the IPRangesKey for external deletions via keyspace notifications.
+// After the key has been deleted, expired or evicted, the watcher restores the global ±inf boundaries
+// under the lock of the Client and the next call that reads the ranges fails with ErrKeyDeleted once,
+// which allows the following calls to work on the now empty database again.
+//
+// Keyspace notifications must be enabled on the server for generic and expiration events, e.g.
+//
+//	CONFIG SET notify-keyspace-events Kgxe
+//
+// Keyspace notifications are only published by the node that stores the key. In a redis cluster the
+// subscription is placed on the master that owns the hash slot of the {goripr} hash tag, which is why
+// deletions are missed after a failover or resharding moved that slot, until the Client is recreated.
+// The notifications must be enabled on every master of the cluster.
+func WithKeyWatcher() Option {
+	return func(c *Client) {
+		c.watchKey = true
+	}
+}
+
+// keyDeletionEvents are the keyspace events that remove the IPRangesKey.
+var keyDeletionEvents = map[string]bool{
+	"del":         true,
+	"expire":      true,
+	"expired":     true,
+	"evicted":     true,
+	"rename_from": true,
+}
+
+// keyRestoreInterval is the interval in which failed restorations of the global boundaries are retried.
+const keyRestoreInterval = time.Second
+
+// startKeyWatcher subscribes to the keyspace notifications of the IPRangesKey in the database db.
+// It returns as soon as the subscription is confirmed.
+func (c *Client) startKeyWatcher(ctx context.Context, db int) error {
+	channel := fmt.Sprintf("__keyspace@%d__:%s", db, IPRangesKey)
+
+	ps := c.rdb.Subscribe(ctx, channel)
+	_, err := ps.Receive(ctx)
+	if err != nil {
+		ps.Close()
+		return err
+	}
+	c.watcher = ps
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	c.watcherCancel = cancel
+
+	go func(ch <-chan *redis.Message) {
+		for msg := range ch {
+			if keyDeletionEvents[msg.Payload] {
+				c.restoreKey(watchCtx)
+			}
+		}
+	}(ps.Channel())
+	return nil
+}
+
+// restoreKey restores the global boundaries under the write lock after the IPRangesKey was deleted
+// and marks the deletion for checkKey afterwards. Failed restorations are retried until they succeed
+// or ctx is canceled by Close.
+func (c *Client) restoreKey(ctx context.Context) {
+	for {
+		c.mu.Lock()
+		err := c.init(ctx)
+		c.mu.Unlock()
+		if err == nil {
+			c.keyDeleted.Store(true)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(keyRestoreInterval):
+		}
+	}
+}
+
+// checkKey returns ErrKeyDeleted once after the key watcher restored the global boundaries
+// because the IPRangesKey was deleted. It does not modify the database, which is why it
+// can be called with either the read or the write lock held.
+func (c *Client) checkKey() error {
+	if c.keyDeleted.CompareAndSwap(true, false) {
+		return ErrKeyDeleted
+	}
+	return nil
+}
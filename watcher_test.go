@@ -0,0 +1,65 @@
+package goripr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWithKeyWatcher(t *testing.T) {
+	ctx := context.TODO()
+
+	rdb, err := NewClient(ctx, Options{Addr: redisAddr, DB: 2}, WithKeyWatcher())
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer rdb.Close()
+
+	if err := rdb.Reset(ctx); err != nil {
+		t.Fatalf("rdb.Reset() error = %v", err)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "watched"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+
+	// delete the key externally and emit the keyspace notification manually,
+	// as not every redis server implementation supports keyspace notifications
+	if err := rdb.rdb.Del(ctx, IPRangesKey).Err(); err != nil {
+		t.Fatalf("Del() error = %v", err)
+	}
+	channel := fmt.Sprintf("__keyspace@%d__:%s", 2, IPRangesKey)
+	if err := rdb.rdb.Publish(ctx, channel, "del").Err(); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err = rdb.WaitForCondition(waitCtx, func(c *Client) (bool, error) {
+		return c.keyDeleted.Load(), nil
+	}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("key deletion was not noticed: %v", err)
+	}
+
+	// the watcher restored the global boundaries before it marked the deletion
+	if _, _, err := rdb.GlobalBoundaries(ctx); err != nil {
+		t.Fatalf("rdb.GlobalBoundaries() error = %v", err)
+	}
+
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrKeyDeleted) {
+		t.Fatalf("rdb.Find() error = %v, want %v", err, ErrKeyDeleted)
+	}
+
+	// ErrKeyDeleted is only returned once
+	if _, err := rdb.Find(ctx, "10.0.0.1"); !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("rdb.Find() error = %v, want %v", err, ErrIPNotFound)
+	}
+	if err := rdb.Insert(ctx, "10.0.0.0/24", "restored"); err != nil {
+		t.Fatalf("rdb.Insert() error = %v", err)
+	}
+	if reason, err := rdb.Find(ctx, "10.0.0.1"); err != nil || reason != "restored" {
+		t.Fatalf("rdb.Find() = %q, %v, want %q", reason, err, "restored")
+	}
+}